@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// benchmarkFieldInits returns a template function that generates a list of Go statements that
+// populate every field of a message with synthetic data for use in generated benchmarks.  The
+// statements assign values to the fields of a local variable named m.
+//
+// Nested message fields are populated with empty messages, repeated fields and maps contain a single
+// element, and only the first field of each oneof is set.  Groups and extensions are not populated.
+func benchmarkFieldInits(protoFile *protogen.File, names specialNames, goPackageForFile map[string]string) func(*protogen.Message) []string {
+	importPrefix := getImportPrefix(protoFile, goPackageForFile)
+	mapType := mapFieldGoType(protoFile, goPackageForFile)
+	safeName := getSafeFieldName(names)
+	return func(msg *protogen.Message) []string {
+		var stmts []string
+		for _, fld := range benchmarkFields(msg) {
+			switch oneof := fld.Desc.ContainingOneof(); {
+			case oneof != nil && !oneof.IsSynthetic():
+				stmts = append(stmts, fmt.Sprintf("m.%s = &%s{%s: %s}",
+					safeName(fld.Oneof.GoName), safeName(fld.GoIdent.GoName), safeName(fld.GoName), benchmarkValue(fld, importPrefix)))
+			case fld.Desc.IsMap():
+				k, v := fld.Message.Fields[0], fld.Message.Fields[1]
+				stmts = append(stmts, fmt.Sprintf("m.%s = %s{%s: %s}",
+					safeName(fld.GoName), mapType(fld), benchmarkValue(k, importPrefix), benchmarkValue(v, importPrefix)))
+			case fld.Desc.IsList():
+				stmts = append(stmts, fmt.Sprintf("m.%s = []%s{%s}",
+					safeName(fld.GoName), benchmarkGoType(fld, importPrefix), benchmarkValue(fld, importPrefix)))
+			case benchmarkUsesPointer(fld):
+				stmts = append(stmts, fmt.Sprintf("m.%s = func() *%s { v := %s; return &v }()",
+					safeName(fld.GoName), benchmarkGoType(fld, importPrefix), benchmarkValue(fld, importPrefix)))
+			default:
+				stmts = append(stmts, fmt.Sprintf("m.%s = %s", safeName(fld.GoName), benchmarkValue(fld, importPrefix)))
+			}
+		}
+		return stmts
+	}
+}
+
+// benchmarkImports returns a template function that returns the set of distinct import paths required
+// by the synthetic data for msgs.  Unlike getAdditionalImports, only the fields that are populated by
+// the generated benchmarks are considered so the generated code does not contain unused imports.
+//
+// The return value is a map[string]string where the key is the import path and the value is the import
+// alias to use in the Go code.
+func benchmarkImports(protoFile *protogen.File, goPackageForFile map[string]string) func([]*protogen.Message) map[string]string {
+	return func(msgs []*protogen.Message) map[string]string {
+		res := map[string]string{}
+		addImport := func(ident protogen.GoIdent, fd protoreflect.FileDescriptor) {
+			if ident.GoImportPath != protoFile.GoImportPath {
+				res[ident.GoImportPath.String()] = goPackageForFile[fd.Path()]
+			}
+		}
+		for _, msg := range msgs {
+			for _, fld := range benchmarkFields(msg) {
+				if fld.Desc.IsMap() {
+					fld = fld.Message.Fields[1]
+				}
+				switch fld.Desc.Kind() {
+				case protoreflect.MessageKind:
+					addImport(fld.Message.GoIdent, fld.Message.Desc.ParentFile())
+				case protoreflect.EnumKind:
+					addImport(fld.Enum.GoIdent, fld.Enum.Desc.ParentFile())
+				default:
+					// nothing to do
+				}
+			}
+		}
+		return res
+	}
+}
+
+// benchmarkFields returns the fields of msg that are populated with synthetic data by the generated
+// benchmarks, which excludes groups and all but the first field of each oneof.
+func benchmarkFields(msg *protogen.Message) []*protogen.Field {
+	res := make([]*protogen.Field, 0, len(msg.Fields))
+	for _, fld := range msg.Fields {
+		if fld.Desc.Kind() == protoreflect.GroupKind {
+			continue
+		}
+		if oneof := fld.Desc.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() && fld.Oneof.Fields[0] != fld {
+			continue
+		}
+		res = append(res, fld)
+	}
+	return res
+}
+
+// benchmarkUsesPointer returns true if the generated Go code for fld uses a pointer to a scalar value,
+// which is the case for proto2 fields and proto3 optional fields.
+func benchmarkUsesPointer(fld *protogen.Field) bool {
+	switch fld.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind, protoreflect.BytesKind:
+		return false
+	default:
+		return fld.Desc.Syntax() == protoreflect.Proto2 || fld.Desc.ContainingOneof() != nil
+	}
+}
+
+// benchmarkGoType returns the Go type of a single value of fld
+func benchmarkGoType(fld *protogen.Field, importPrefix func(interface{}) string) string {
+	switch fld.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]byte"
+	case protoreflect.EnumKind:
+		return importPrefix(fld.Enum) + fld.Enum.GoIdent.GoName
+	case protoreflect.MessageKind:
+		return "*" + importPrefix(fld.Message) + fld.Message.GoIdent.GoName
+	default:
+		return fmt.Sprintf("<<invalid>> /*%v*/", fld.Desc.Kind())
+	}
+}
+
+// benchmarkValue returns a Go expression for a synthetic, non-zero value of fld
+func benchmarkValue(fld *protogen.Field, importPrefix func(interface{}) string) string {
+	switch fld.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "true"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32(42)"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64(1138)"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32(42)"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64(1138)"
+	case protoreflect.FloatKind:
+		return "float32(3.14)"
+	case protoreflect.DoubleKind:
+		return "float64(2.718)"
+	case protoreflect.StringKind:
+		return strconv.Quote("value of " + string(fld.Desc.Name()))
+	case protoreflect.BytesKind:
+		return fmt.Sprintf("[]byte(%s)", strconv.Quote(string(fld.Desc.Name())))
+	case protoreflect.EnumKind:
+		// use the last defined value so that the value is non-zero whenever possible
+		vals := fld.Enum.Values
+		return fmt.Sprintf("%s(%d)", benchmarkGoType(fld, importPrefix), vals[len(vals)-1].Desc.Number())
+	case protoreflect.MessageKind:
+		return "&" + importPrefix(fld.Message) + fld.Message.GoIdent.GoName + "{}"
+	default:
+		return fmt.Sprintf("<<invalid>> /*%v*/", fld.Desc.Kind())
+	}
+}
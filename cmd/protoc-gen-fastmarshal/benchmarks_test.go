@@ -0,0 +1,216 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const benchmarkTestGoPackage = "github.com/CrowdStrike/csproto/cmd/protoc-gen-fastmarshal/benchtest;benchpb"
+
+// newBenchmarkTestRequest returns a code generator request for a test .proto file that contains all
+// of the field types supported by the generated benchmarks
+func newBenchmarkTestRequest(params string) *pluginpb.CodeGeneratorRequest {
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+	}
+	typed := func(f *descriptorpb.FieldDescriptorProto, typeName string) *descriptorpb.FieldDescriptorProto {
+		f.TypeName = proto.String(typeName)
+		return f
+	}
+	oneof := func(f *descriptorpb.FieldDescriptorProto, idx int32, synthetic bool) *descriptorpb.FieldDescriptorProto {
+		f.OneofIndex = proto.Int32(idx)
+		if synthetic {
+			f.Proto3Optional = proto.Bool(true)
+		}
+		return f
+	}
+	const (
+		optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	)
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("bench.proto"),
+		Package:    proto.String("csproto.benchtest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String(benchmarkTestGoPackage),
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name: proto.String("Kind"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String("KIND_ONE"), Number: proto.Int32(1)},
+				},
+			},
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Everything"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("b", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, optional),
+					field("i32", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional),
+					field("i64", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional),
+					field("u32", 4, descriptorpb.FieldDescriptorProto_TYPE_UINT32, optional),
+					field("u64", 5, descriptorpb.FieldDescriptorProto_TYPE_UINT64, optional),
+					field("s32", 6, descriptorpb.FieldDescriptorProto_TYPE_SINT32, optional),
+					field("s64", 7, descriptorpb.FieldDescriptorProto_TYPE_SINT64, optional),
+					field("f32", 8, descriptorpb.FieldDescriptorProto_TYPE_FIXED32, optional),
+					field("f64", 9, descriptorpb.FieldDescriptorProto_TYPE_FIXED64, optional),
+					field("sf32", 10, descriptorpb.FieldDescriptorProto_TYPE_SFIXED32, optional),
+					field("sf64", 11, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64, optional),
+					field("flt", 12, descriptorpb.FieldDescriptorProto_TYPE_FLOAT, optional),
+					field("dbl", 13, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional),
+					field("str", 14, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+					field("byts", 15, descriptorpb.FieldDescriptorProto_TYPE_BYTES, optional),
+					typed(field("kind", 16, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional), ".csproto.benchtest.Kind"),
+					typed(field("nested", 17, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional), ".csproto.benchtest.Everything.Nested"),
+					typed(field("ts", 18, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional), ".google.protobuf.Timestamp"),
+					field("strs", 19, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated),
+					field("i32s", 20, descriptorpb.FieldDescriptorProto_TYPE_INT32, repeated),
+					typed(field("nesteds", 21, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated), ".csproto.benchtest.Everything.Nested"),
+					typed(field("attrs", 22, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated), ".csproto.benchtest.Everything.AttrsEntry"),
+					oneof(field("choice_a", 23, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional), 0, false),
+					oneof(field("choice_b", 24, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional), 0, false),
+					oneof(field("opt", 25, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional), 1, true),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Nested"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+						},
+					},
+					{
+						Name: proto.String("AttrsEntry"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+							field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+						},
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{
+					{Name: proto.String("choice")},
+					{Name: proto.String("_opt")},
+				},
+			},
+		},
+	}
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"bench.proto"},
+		Parameter:      proto.String(params),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			fd,
+		},
+	}
+}
+
+// generateBenchmarkTestFiles runs the fastmarshal generator with benchmarks enabled, plus the standard
+// protoc-gen-go generator, against the test .proto file and returns the generated files.
+func generateBenchmarkTestFiles(t *testing.T) []*pluginpb.CodeGeneratorResponse_File {
+	t.Helper()
+	opts := options{
+		specialNames:       make(specialNames),
+		apiVersion:         protoAPIVersion("v2"),
+		generateBenchmarks: true,
+	}
+	plugin, err := protogen.Options{}.New(newBenchmarkTestRequest(""))
+	if err != nil {
+		t.Fatalf("unable to initialize the code generator: %v", err)
+	}
+	plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	for _, f := range plugin.Files {
+		if f.Generate {
+			internal_gengo.GenerateFile(plugin, f)
+		}
+	}
+	if err := doGenerate(&opts)(plugin); err != nil {
+		t.Fatalf("unexpected error generating code: %v", err)
+	}
+	resp := plugin.Response()
+	if resp.Error != nil {
+		t.Fatalf("unexpected error from code generator: %s", resp.GetError())
+	}
+	return resp.GetFile()
+}
+
+func TestGenerateBenchmarks(t *testing.T) {
+	files := generateBenchmarkTestFiles(t)
+
+	var content string
+	for _, f := range files {
+		if strings.HasSuffix(f.GetName(), ".pb.fm_bench_test.go") {
+			content = f.GetContent()
+		}
+	}
+	if content == "" {
+		t.Fatalf("no benchmark file was generated")
+	}
+	for _, msg := range []string{"Everything", "Everything_Nested"} {
+		for _, fn := range []string{"BenchmarkMarshal_", "BenchmarkUnmarshal_", "BenchmarkSize_"} {
+			if !strings.Contains(content, "func "+fn+msg+"(b *testing.B) {") {
+				t.Errorf("generated code does not contain %s%s", fn, msg)
+			}
+		}
+	}
+	if !strings.Contains(content, "b.ReportAllocs()") {
+		t.Errorf("generated benchmarks do not report allocations")
+	}
+}
+
+func TestGeneratedBenchmarksCompileAndRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping compilation of generated code in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not available")
+	}
+	// the generated code is written to a temporary package inside of this module so that it can be
+	// compiled without resolving any additional dependencies.  the leading underscore excludes the
+	// directory from ./... patterns.
+	dir, err := os.MkdirTemp(".", "_benchtest")
+	if err != nil {
+		t.Fatalf("unable to create temp directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	for _, f := range generateBenchmarkTestFiles(t) {
+		// generated file names are relative to the Go import path
+		p := filepath.Join(dir, filepath.Base(f.GetName()))
+		if err := os.WriteFile(p, []byte(f.GetContent()), 0o600); err != nil {
+			t.Fatalf("unable to write %s: %v", p, err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "test", "-run", "^$", "-bench", ".", "-benchtime", "1x", "./"+filepath.Base(dir))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated benchmarks failed: %v\n%s", err, out)
+	}
+	for _, fn := range []string{"BenchmarkMarshal_Everything", "BenchmarkUnmarshal_Everything", "BenchmarkSize_Everything"} {
+		if !strings.Contains(string(out), fn) {
+			t.Errorf("expected benchmark output to include %s\n%s", fn, out)
+		}
+	}
+}
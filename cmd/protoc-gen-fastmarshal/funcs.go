@@ -204,6 +204,8 @@ func addProtoFunctions(fm template.FuncMap, protoFile *protogen.File, names spec
 	fm["mapFieldGoType"] = mapFieldGoType(protoFile, goPackageForFile)
 	fm["hasRequiredFields"] = hasRequiredFields(protoFile)
 	fm["getSafeFieldName"] = getSafeFieldName(names)
+	fm["benchmarkFieldInits"] = benchmarkFieldInits(protoFile, names, goPackageForFile)
+	fm["benchmarkImports"] = benchmarkImports(protoFile, goPackageForFile)
 	return fm
 }
 
@@ -70,6 +70,41 @@ func generateSingle(plugin *protogen.Plugin, req generateRequest) error {
 	return nil
 }
 
+// generateBenchmarks renders the benchmarks for all messages in the Protobuf file to a single _test.go file
+func generateBenchmarks(plugin *protogen.Plugin, req generateRequest) error {
+	type genArgsBenchmarks struct {
+		ProtoDesc  *protogen.File
+		APIVersion string
+	}
+	args := genArgsBenchmarks{
+		ProtoDesc:  req.ProtoDesc,
+		APIVersion: req.APIVersion,
+	}
+
+	goPackageForFile := make(map[string]string, len(plugin.Files))
+	for _, f := range plugin.Files {
+		goPackageForFile[f.Desc.Path()] = string(f.GoPackageName)
+	}
+	funcs := codeGenFunctions(req.ProtoDesc, req.SpecialNames, goPackageForFile)
+	for k, v := range req.Funcs {
+		funcs[k] = v
+	}
+	ct, err := loadTemplateFromEmbedded(funcs)
+	if err != nil {
+		return fmt.Errorf("unable to load embedded content templates: %w", err)
+	}
+	content, err := renderNamedTemplate(ct, "Benchmarks", args)
+	if err != nil {
+		return fmt.Errorf("unable to generate benchmarks from content template: %w", err)
+	}
+
+	res := plugin.NewGeneratedFile(req.NameTemplate, req.ProtoDesc.GoImportPath)
+	if _, err = res.Write([]byte(content)); err != nil {
+		return fmt.Errorf("error while writing benchmarks file: %w", err)
+	}
+	return nil
+}
+
 func generatePerMessage(plugin *protogen.Plugin, req generateRequest) error {
 	type genArgsPerFile struct {
 		Now                time.Time
@@ -32,6 +32,10 @@ Supported options (beyond those provided by google.golang.org/protobuf/compiler/
   enableunsafedecode=true|false
 	- enable using unsafe code to decode string values without making copies for better performance
 	- default is false
+  generatebenchmarks=true|false
+    - if true, also generate a "[protofile].pb.fm_bench_test.go" file containing Marshal, Unmarshal,
+      and Size benchmarks for each message, populated with synthetic data
+    - default is false
 
 Direct Usage: protoc-gen-fastmarshal [version|help]
   version: writes the version, commit hash, build info for the binary to stdout
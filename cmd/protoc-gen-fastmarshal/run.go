@@ -23,6 +23,7 @@ type options struct {
 	filePerMessage     bool
 	specialNames       specialNames
 	enableUnsafeDecode bool
+	generateBenchmarks bool
 }
 
 // protoAPIVersion defines a string flag that can contain either "v1" or "v2"
@@ -103,6 +104,7 @@ func run() {
 	flags.BoolVar(&runOptions.filePerMessage, "filepermessage", false, "if true, outputs a separate file for each message")
 	flags.Var(&runOptions.specialNames, "specialname", "if set, specifies field names to be munged in the generated code")
 	flags.BoolVar(&runOptions.enableUnsafeDecode, "enableunsafedecode", false, "if true, enables using unsafe code to decode strings for better perf")
+	flags.BoolVar(&runOptions.generateBenchmarks, "generatebenchmarks", false, "if true, also outputs a _test.go file with benchmarks for each message")
 
 	// load and run the generator
 	genOptions := protogen.Options{
@@ -150,6 +152,13 @@ func doGenerate(opts *options) func(*protogen.Plugin) error {
 			if err := generate(plugin, req); err != nil {
 				return err
 			}
+			if opts.generateBenchmarks {
+				// benchmarks are always written to a single file: "[protofile].pb.fm_bench_test.go"
+				req.NameTemplate = protoFile.GeneratedFilenamePrefix + `.pb.fm_bench_test.go`
+				if err := generateBenchmarks(plugin, req); err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	}
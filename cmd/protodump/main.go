@@ -8,10 +8,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
-	"strings"
 	"time"
 
 	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/internal/protodump"
 )
 
 func main() {
@@ -19,6 +19,7 @@ func main() {
 		inputFile       string
 		expandPaths     tagPaths
 		stringPaths     tagPaths
+		verbose         bool
 		showVersionInfo bool
 		showUsage       bool
 	)
@@ -28,6 +29,7 @@ func main() {
 	fset.StringVar(&inputFile, "file", "", "The path to the Protobuf data to be decoded. (optional, reads from stdin if not specified)")
 	fset.Var(&expandPaths, "expand", "One or more 'paths' to length-delimited fields in the message that should be expanded (optional)")
 	fset.Var(&stringPaths, "strings", "One or more 'paths' to length-delimited fields in the message that contain string data (optional)")
+	fset.BoolVar(&verbose, "verbose", false, "Prints the byte offset and the raw bytes of the tag key and value for each field (optional)")
 	fset.BoolVar(&showVersionInfo, "version", false, "Shows version information")
 	fset.BoolVar(&showUsage, "help", false, "Shows usage information")
 
@@ -70,7 +72,7 @@ func main() {
 			os.Exit(1)
 		}
 	}
-	err = dumpProtoFile(f, &expandPaths, &stringPaths)
+	err = dumpProtoFile(f, &expandPaths, &stringPaths, verbose)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -86,12 +88,17 @@ Two additional options are supported for length-delimited fields.  For nested me
 '-expand' flag can be used to "recurse" into those messages.  The '-strings' flag can be used to output
 field values as strings instead of raw bytes.  Both parameters accept one or more "tag path" values,
 which are dot-separated lists of integer field tags that indicate the nesting structure of the message
-data.
+data.  A tag of '*' (or 0) is a wildcard that matches any field at that level.
+
+For debugging encoding issues, the '-verbose' flag additionally prints the byte offset of each field
+within the input data along with the raw bytes of the field's tag key and value.
 
 Examples:
 	cat message.bin | protodump
 	protodump -file message.bin
-	protodump -file message.bin -expand "3" -expand "4.4" -strings "1,2,3.1,3.2`
+	protodump -file message.bin -expand "3" -expand "4.4" -strings "1,2,3.1,3.2"
+	protodump -file message.bin -expand "*" -strings "*.2"
+	protodump -file message.bin -expand "*" -verbose`
 
 func printUsage(fset *flag.FlagSet) func() {
 	return func() {
@@ -138,7 +145,7 @@ func echoVersion() {
 	fmt.Printf("version: %s\ncommit:  %s\ndate:    %s\nbuiltBy: %s\n", version, commit, date, builtBy)
 }
 
-func dumpProtoFile(input io.Reader, expand *tagPaths, stringPaths *tagPaths) error {
+func dumpProtoFile(input io.Reader, expand *tagPaths, stringPaths *tagPaths, verbose bool) error {
 	data, err := io.ReadAll(input)
 	if err != nil {
 		return err
@@ -147,6 +154,7 @@ func dumpProtoFile(input io.Reader, expand *tagPaths, stringPaths *tagPaths) err
 		indent:  0,
 		expand:  expand,
 		strings: stringPaths,
+		verbose: verbose,
 	}
 	return dumpProto(os.Stdout, csproto.NewDecoder(data), tagPath{}, conf)
 }
@@ -159,6 +167,10 @@ type dumpConfig struct {
 	indent  int
 	expand  tagPathMatcher
 	strings tagPathMatcher
+	// if true, output the offset and raw bytes for each field
+	verbose bool
+	// the offset of the data being decoded within the original input, for nested messages
+	baseOffset int
 }
 
 func (conf dumpConfig) isStringField(tp tagPath) bool {
@@ -170,61 +182,67 @@ func (conf dumpConfig) shouldExpand(tp tagPath) bool {
 }
 
 func dumpProto(w io.Writer, dec *csproto.Decoder, parentTagPath tagPath, conf dumpConfig) error {
-	prefix := strings.Repeat(" ", 2*conf.indent)
 	bw := bufio.NewWriter(w)
 	defer bw.Flush()
+	p := protodump.NewPrinter(bw, conf.indent)
 
 	for dec.More() {
+		fieldStart := dec.Offset()
 		tag, wireType, err := dec.DecodeTag()
 		if err != nil {
 			return err
 		}
+		valueStart := dec.Offset()
 
 		thisTagPath := append(parentTagPath, tag)
 
-		_, _ = bw.WriteString(fmt.Sprintf("%stag: %d, wire type: %s\n", prefix, tag, wireType))
+		if conf.verbose {
+			tagKey, _ := dec.Slice(fieldStart, valueStart)
+			p.WriteOffset(conf.baseOffset + fieldStart)
+			p.WriteTagKeyBytes(tagKey)
+		}
+		p.WriteTag(tag, wireType)
 		switch wireType {
 		case csproto.WireTypeVarint:
 			vv, err := dec.DecodeInt64()
 			if err != nil {
 				return err
 			}
-			_, _ = bw.WriteString(fmt.Sprintf("%s  varint: %d\n", prefix, vv))
+			writeValueBytes(p, dec, valueStart, conf)
+			p.WriteVarint(vv)
 		case csproto.WireTypeFixed32:
 			f32, err := dec.DecodeFixed32()
 			if err != nil {
 				return err
 			}
-			_, _ = bw.WriteString(fmt.Sprintf("%s  fixed32: %d\n", prefix, f32))
+			writeValueBytes(p, dec, valueStart, conf)
+			p.WriteFixed32(f32)
 		case csproto.WireTypeFixed64:
 			f64, err := dec.DecodeFixed64()
 			if err != nil {
 				return err
 			}
-			_, _ = bw.WriteString(fmt.Sprintf("%s  fixed64: %d\n", prefix, f64))
+			writeValueBytes(p, dec, valueStart, conf)
+			p.WriteFixed64(f64)
 		case csproto.WireTypeLengthDelimited:
 			ldv, err := dec.DecodeBytes()
 			if err != nil {
 				return err
 			}
-			_, _ = bw.WriteString(fmt.Sprintf("%s  length: %d\n", prefix, len(ldv)))
+			writeValueBytes(p, dec, valueStart, conf)
+			p.WriteLength(len(ldv))
 			switch {
 			case conf.isStringField(thisTagPath):
-				_, _ = bw.WriteString(fmt.Sprintf("%s  string: %s\n", prefix, string(ldv)))
+				p.WriteStringValue(string(ldv))
 			default:
-				_, _ = bw.WriteString(fmt.Sprintf("%s  [", prefix))
-				for i, b := range ldv {
-					if i > 0 {
-						_, _ = bw.WriteRune(',')
-					}
-					_, _ = bw.WriteString(fmt.Sprintf("0x%02X", b))
-				}
-				_, _ = bw.WriteString("]\n")
+				p.WriteBytesValue(ldv)
 				if conf.shouldExpand(thisTagPath) {
 					_ = bw.Flush()
-					conf.indent++
-					err = dumpProto(w, csproto.NewDecoder(ldv), thisTagPath, conf)
-					conf.indent--
+					nestedConf := conf
+					nestedConf.indent++
+					// the nested message data starts after the length prefix
+					nestedConf.baseOffset += dec.Offset() - len(ldv)
+					err = dumpProto(w, csproto.NewDecoder(ldv), thisTagPath, nestedConf)
 					if err != nil {
 						return err
 					}
@@ -237,3 +255,13 @@ func dumpProto(w io.Writer, dec *csproto.Decoder, parentTagPath tagPath, conf du
 	}
 	return nil
 }
+
+// writeValueBytes writes the raw bytes of the field value that starts at valueStart and ends at the
+// decoder's current offset when verbose output is enabled.
+func writeValueBytes(p *protodump.Printer, dec *csproto.Decoder, valueStart int, conf dumpConfig) {
+	if !conf.verbose {
+		return
+	}
+	v, _ := dec.Slice(valueStart, dec.Offset())
+	p.WriteValueBytes(v)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+func TestDumpProtoVerbose(t *testing.T) {
+	t.Parallel()
+	data := []byte{
+		// 1 (varint): 150
+		0x08, 0x96, 0x01,
+		// 2 (fixed32): 1138
+		0x15, 0x72, 0x04, 0x00, 0x00,
+		// 3 (length-delimited): nested message { 1 (varint): 1, 2 (length-delimited): "a" }
+		0x1A, 0x05, 0x08, 0x01, 0x12, 0x01, 0x61,
+		// 4 (fixed64): 1138
+		0x21, 0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	var expand, strs tagPaths
+	require.NoError(t, expand.Set("3"))
+	conf := dumpConfig{
+		expand:  &expand,
+		strings: &strs,
+		verbose: true,
+	}
+
+	var buf bytes.Buffer
+	err := dumpProto(&buf, csproto.NewDecoder(data), tagPath{}, conf)
+	require.NoError(t, err)
+
+	type verboseField struct {
+		offset     int
+		tagKey     []byte
+		valueBytes []byte
+		varint     *int64
+	}
+	var fields []*verboseField
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "offset: 0x"):
+			v, err := strconv.ParseInt(strings.TrimPrefix(line, "offset: 0x"), 16, 64)
+			require.NoError(t, err)
+			fields = append(fields, &verboseField{offset: int(v)})
+		case strings.HasPrefix(line, "tag key bytes: "):
+			fields[len(fields)-1].tagKey = parseByteList(t, strings.TrimPrefix(line, "tag key bytes: "))
+		case strings.HasPrefix(line, "value bytes: "):
+			fields[len(fields)-1].valueBytes = parseByteList(t, strings.TrimPrefix(line, "value bytes: "))
+		case strings.HasPrefix(line, "varint: "):
+			v, err := strconv.ParseInt(strings.TrimPrefix(line, "varint: "), 10, 64)
+			require.NoError(t, err)
+			fields[len(fields)-1].varint = &v
+		}
+	}
+	require.NoError(t, sc.Err())
+
+	// 4 top-level fields plus 2 fields in the expanded nested message
+	require.Len(t, fields, 6)
+	assert.Equal(t, []int{0, 3, 8, 10, 12, 15}, []int{fields[0].offset, fields[1].offset, fields[2].offset, fields[3].offset, fields[4].offset, fields[5].offset})
+	for i, f := range fields {
+		if i > 0 {
+			assert.Greater(t, f.offset, fields[i-1].offset, "offsets should be monotonically increasing")
+		}
+		// the displayed bytes should match the input data at the displayed offset
+		raw := append(append([]byte(nil), f.tagKey...), f.valueBytes...)
+		require.LessOrEqual(t, f.offset+len(raw), len(data))
+		assert.Equal(t, data[f.offset:f.offset+len(raw)], raw, "bytes for the field at offset %d do not match the input", f.offset)
+		// and should be consistent with the decoded values
+		if f.varint != nil {
+			v, _, err := csproto.DecodeVarint(f.valueBytes)
+			assert.NoError(t, err)
+			assert.Equal(t, *f.varint, int64(v))
+		}
+	}
+	assert.Equal(t, uint32(1138), binary.LittleEndian.Uint32(fields[1].valueBytes))
+	assert.Equal(t, uint64(1138), binary.LittleEndian.Uint64(fields[5].valueBytes))
+}
+
+func TestDumpProtoNotVerbose(t *testing.T) {
+	t.Parallel()
+	data := []byte{0x08, 0x96, 0x01}
+	conf := dumpConfig{
+		expand:  &tagPaths{},
+		strings: &tagPaths{},
+	}
+
+	var buf bytes.Buffer
+	err := dumpProto(&buf, csproto.NewDecoder(data), tagPath{}, conf)
+	require.NoError(t, err)
+	assert.Equal(t, "tag: 1, wire type: varint\n  varint: 150\n", buf.String())
+}
+
+// parseByteList parses a list of bytes in the "[0x01,0x02]" format written by protodump.Printer
+func parseByteList(t *testing.T, s string) []byte {
+	t.Helper()
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	if s == "" {
+		return nil
+	}
+	var res []byte
+	for _, v := range strings.Split(s, ",") {
+		b, err := strconv.ParseUint(strings.TrimPrefix(v, "0x"), 16, 8)
+		require.NoError(t, err)
+		res = append(res, byte(b))
+	}
+	return res
+}
@@ -13,7 +13,9 @@ import (
 // only the integer tags defined in the .proto IDL.
 //
 // Each element in the path represents a field at that level of the encoded message.  A value of 0 is
-// used to indicate that the path applies to all fields at that level.
+// a wildcard that indicates that the path applies to all fields at that level, so "0.2" matches field
+// 2 inside of any nested message at the top level.  When parsing paths from flags, "*" can be used in
+// place of 0.
 //
 // The path "1" refereces the Outer.id field in the example Protobuf IDL below.  Similarly, the path
 // "3.2" references the Inner.timestamp field inside of Outer.nested.
@@ -42,19 +44,47 @@ func (tp tagPath) String() string {
 }
 
 // Matches accepts a tag path and returns a boolean value indicating whether or not this path refers
-// to the same field as p.
+// to the same field as p.  Wildcard (0) elements in this path match any tag at that position in p.
 func (tp tagPath) Matches(p tagPath) bool {
 	if len(tp) == 0 || len(tp) != len(p) {
 		return false
 	}
 	for i, t := range tp {
-		if t != p[i] {
+		if t != 0 && t != p[i] {
 			return false
 		}
 	}
 	return true
 }
 
+// Contains returns a boolean value indicating whether or not sub is the same path as, or a sub-path of,
+// this path.  For example, the path "1" contains both "1" and "1.2".  As with Matches(), wildcard (0)
+// elements in this path match any tag at that position in sub, and an empty path never matches.
+func (tp tagPath) Contains(sub tagPath) bool {
+	if len(tp) == 0 || len(sub) < len(tp) {
+		return false
+	}
+	return tp.Matches(sub[:len(tp)])
+}
+
+// IsPrefix returns a boolean value indicating whether or not this path is a proper prefix of p, which
+// is the same as Contains(p) except that it returns false if the two paths are the same length.
+func (tp tagPath) IsPrefix(p tagPath) bool {
+	return len(tp) < len(p) && tp.Contains(p)
+}
+
+// Prefix returns a copy of the first n elements of this path, or of the entire path if n is larger than
+// the length of the path.  If n is less than or equal to zero, the result is an empty path.
+func (tp tagPath) Prefix(n int) tagPath {
+	if n <= 0 {
+		return tagPath{}
+	}
+	if n > len(tp) {
+		n = len(tp)
+	}
+	return append(tagPath{}, tp[:n]...)
+}
+
 // tagPaths defines a custom flag.Value implementation for a flag that can store one or more Protobuf
 // tag "paths".
 type tagPaths struct {
@@ -97,6 +127,10 @@ func (v *tagPaths) Set(value string) error {
 			if t == "" {
 				continue
 			}
+			if t == "*" {
+				thisPath = append(thisPath, 0)
+				continue
+			}
 			tag, err := strconv.Atoi(t)
 			if err != nil {
 				return fmt.Errorf("invalid tag token %q, must be a valid integer Protobuf field tag", t)
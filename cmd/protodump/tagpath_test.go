@@ -46,6 +46,8 @@ func TestTagPathArgParse(t *testing.T) {
 		{"multiple single values", "1,2", false, []tagPath{{1}, {2}}},
 		{"single dotted value", "1.2", false, []tagPath{{1, 2}}},
 		{"multiple dotted values", "1.2,3.4", false, []tagPath{{1, 2}, {3, 4}}},
+		{"wildcard value", "0.2", false, []tagPath{{0, 2}}},
+		{"star wildcard value", "*.2,3.*", false, []tagPath{{0, 2}, {3, 0}}},
 		// failure cases
 		{"single non-integer value", "x", true, nil},
 		{"single non-integer dotted value", "1.x", true, nil},
@@ -126,6 +128,42 @@ func TestTagPathMatch(t *testing.T) {
 			against:     tagPath{1, 2, 3},
 			shouldMatch: true,
 		},
+		{
+			name:        "wildcard matches tag 1",
+			tp:          tagPath{0, 2},
+			against:     tagPath{1, 2},
+			shouldMatch: true,
+		},
+		{
+			name:        "wildcard matches tag 5",
+			tp:          tagPath{0, 2},
+			against:     tagPath{5, 2},
+			shouldMatch: true,
+		},
+		{
+			name:        "wildcard matches tag 3",
+			tp:          tagPath{0, 2},
+			against:     tagPath{3, 2},
+			shouldMatch: true,
+		},
+		{
+			name:        "wildcard does not match mismatched trailing tag",
+			tp:          tagPath{0, 2},
+			against:     tagPath{3, 4},
+			shouldMatch: false,
+		},
+		{
+			name:        "wildcard does not match longer path",
+			tp:          tagPath{0},
+			against:     tagPath{3, 4},
+			shouldMatch: false,
+		},
+		{
+			name:        "trailing wildcard matches",
+			tp:          tagPath{3, 0},
+			against:     tagPath{3, 4},
+			shouldMatch: true,
+		},
 	}
 	for _, tc := range cases {
 		tc := tc
@@ -137,3 +175,114 @@ func TestTagPathMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestTagPathContainsAndIsPrefix(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name             string
+		tp               tagPath
+		other            tagPath
+		expectedContains bool
+		expectedIsPrefix bool
+	}{
+		{
+			name:             "same path",
+			tp:               tagPath{1, 2},
+			other:            tagPath{1, 2},
+			expectedContains: true,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "sub-path",
+			tp:               tagPath{1},
+			other:            tagPath{1, 2},
+			expectedContains: true,
+			expectedIsPrefix: true,
+		},
+		{
+			name:             "deeply nested sub-path",
+			tp:               tagPath{1, 2},
+			other:            tagPath{1, 2, 3, 4},
+			expectedContains: true,
+			expectedIsPrefix: true,
+		},
+		{
+			name:             "parent path",
+			tp:               tagPath{1, 2},
+			other:            tagPath{1},
+			expectedContains: false,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "sibling paths",
+			tp:               tagPath{1, 2},
+			other:            tagPath{1, 3},
+			expectedContains: false,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "mixed-length sibling paths",
+			tp:               tagPath{1, 2},
+			other:            tagPath{1, 3, 2},
+			expectedContains: false,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "empty receiver",
+			tp:               tagPath{},
+			other:            tagPath{1},
+			expectedContains: false,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "empty argument",
+			tp:               tagPath{1},
+			other:            tagPath{},
+			expectedContains: false,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "both empty",
+			tp:               tagPath{},
+			other:            tagPath{},
+			expectedContains: false,
+			expectedIsPrefix: false,
+		},
+		{
+			name:             "wildcard prefix",
+			tp:               tagPath{0, 2},
+			other:            tagPath{5, 2, 1},
+			expectedContains: true,
+			expectedIsPrefix: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedContains, tc.tp.Contains(tc.other), "Contains() returned an incorrect result")
+			assert.Equal(t, tc.expectedIsPrefix, tc.tp.IsPrefix(tc.other), "IsPrefix() returned an incorrect result")
+		})
+	}
+}
+
+func TestTagPathPrefix(t *testing.T) {
+	t.Parallel()
+	tp := tagPath{1, 2, 3}
+
+	assert.Equal(t, tagPath{}, tp.Prefix(0))
+	assert.Equal(t, tagPath{}, tp.Prefix(-1))
+	assert.Equal(t, tagPath{1, 2, 3}, tp.Prefix(4))
+	for n := 1; n <= len(tp); n++ {
+		prefix := tp.Prefix(n)
+		assert.Len(t, prefix, n)
+		assert.True(t, prefix.Contains(tp), "%v should contain %v", prefix, tp)
+		assert.Equal(t, n < len(tp), prefix.IsPrefix(tp), "%v.IsPrefix(%v) returned an incorrect result", prefix, tp)
+		assert.False(t, tp.IsPrefix(prefix), "%v should not be a prefix of %v", tp, prefix)
+	}
+
+	// the result should be a copy
+	prefix := tp.Prefix(2)
+	prefix[0] = 42
+	assert.Equal(t, tagPath{1, 2, 3}, tp)
+}
@@ -26,6 +26,16 @@ var (
 	ErrInvalidFixed64Data = errors.New("unable to read protobuf fixed 64-bit value")
 	// ErrInvalidPackedData is returned by the decoder when it fails to read a packed repeated value.
 	ErrInvalidPackedData = errors.New("unable to read protobuf packed value")
+	// ErrInvalidGroupData is returned by the decoder when it fails to read a proto2 group.
+	ErrInvalidGroupData = errors.New("unable to read protobuf group")
+	// ErrMessageTooLarge is returned by the decoder when the data is larger than the limit configured
+	// using WithMaxMessageSize(), and when reading a length-delimited message that is larger than the
+	// limit configured using WithMaxDelimitedSize().  It is also returned by the stream package when a
+	// message is too large to be written to or read from a stream.
+	ErrMessageTooLarge = errors.New("protobuf message exceeds the maximum size")
+	// ErrDeprecatedWireType is returned by DecodeTag() when the decoder is configured with
+	// WithStrictWireTypes() and the field uses one of the deprecated group wire types.
+	ErrDeprecatedWireType = errors.New("deprecated protobuf group wire type")
 )
 
 // MaxTagValue is the largest supported protobuf field tag, which is 2^29 - 1 (or 536,870,911)
@@ -61,14 +71,52 @@ type Decoder struct {
 	p      []byte
 	offset int
 	mode   DecoderMode
+	// if greater than zero, the maximum size of the data that can be decoded
+	maxSize int
+	// if true, the deprecated group wire types are rejected
+	strictWireTypes bool
 }
 
-// NewDecoder initializes a new Protobuf decoder to read the provided buffer.
-func NewDecoder(p []byte) *Decoder {
-	return &Decoder{
+// DecoderOption defines a function that sets a specific decoder option
+type DecoderOption func(*Decoder)
+
+// WithMode returns a DecoderOption that configures the decoding behavior, safe vs fastest.  It is
+// equivalent to calling SetMode() after constructing the decoder.
+func WithMode(m DecoderMode) DecoderOption {
+	return func(d *Decoder) {
+		d.mode = m
+	}
+}
+
+// WithMaxMessageSize returns a DecoderOption that limits the size of the data that can be decoded.  If
+// the buffer passed to NewDecoder() is larger than n bytes, all Decode*() methods return an error that
+// wraps ErrMessageTooLarge.  A value of zero or less disables the limit.
+func WithMaxMessageSize(n int) DecoderOption {
+	return func(d *Decoder) {
+		d.maxSize = n
+	}
+}
+
+// WithStrictWireTypes returns a DecoderOption that, when strict is true, causes DecodeTag() to return an
+// error that wraps ErrDeprecatedWireType for fields that use the deprecated group wire types,
+// WireTypeStartGroup and WireTypeEndGroup.
+func WithStrictWireTypes(strict bool) DecoderOption {
+	return func(d *Decoder) {
+		d.strictWireTypes = strict
+	}
+}
+
+// NewDecoder initializes a new Protobuf decoder to read the provided buffer.  The behavior of the
+// decoder can be customized by passing one or more DecoderOption values.
+func NewDecoder(p []byte, opts ...DecoderOption) *Decoder {
+	d := &Decoder{
 		p:      p,
 		offset: 0,
 	}
+	for _, o := range opts {
+		o(d)
+	}
+	return d
 }
 
 // Mode returns the current decoding mode, safe vs fastest.
@@ -109,8 +157,15 @@ func (d *Decoder) Seek(offset int64, whence int) (int64, error) {
 	return int64(d.offset), nil
 }
 
-// Reset moves the read offset back to the beginning of the encoded data
-func (d *Decoder) Reset() {
+// Reset moves the read offset back to the beginning of the encoded data.
+//
+// If a buffer is provided, the decoder switches to reading that buffer instead, which allows a single
+// Decoder to be reused across multiple messages.  Only the first buffer is used, and the decoder's
+// options are retained.
+func (d *Decoder) Reset(p ...[]byte) {
+	if len(p) > 0 {
+		d.p = p[0]
+	}
 	d.offset = 0
 }
 
@@ -119,17 +174,77 @@ func (d *Decoder) More() bool {
 	return d.offset < len(d.p)
 }
 
+// AtEnd indicates if all of the data in the buffer has been read.  It is the inverse of More().
+func (d *Decoder) AtEnd() bool {
+	return !d.More()
+}
+
 // Offset returns the current read offset
 func (d *Decoder) Offset() int {
 	return d.offset
 }
 
+// SetOffset moves the read offset to the specified position, which allows callers to record the value
+// returned by Offset() before a speculative decode and restore it if the decode fails.  Unlike Reset(),
+// the offset can be moved to any position within the data.
+//
+// An error is returned if offset is negative or is past the end of the data.
+func (d *Decoder) SetOffset(offset int) error {
+	if offset < 0 || offset > len(d.p) {
+		return fmt.Errorf("offset (%d) out of bounds, must be between 0 and %d", offset, len(d.p))
+	}
+	d.offset = offset
+	return nil
+}
+
+// Remaining returns the number of bytes that have not yet been read
+func (d *Decoder) Remaining() int {
+	return len(d.p) - d.offset
+}
+
+// Total returns the total number of bytes in the encoded data
+func (d *Decoder) Total() int {
+	return len(d.p)
+}
+
+// Buffer returns the encoded data being read by the decoder.
+//
+// The returned slice is the decoder's underlying buffer, not a copy, and should be treated as read-only.
+// Modifying the contents of the returned slice results in undefined behavior.
+func (d *Decoder) Buffer() []byte {
+	return d.p
+}
+
+// Slice returns the sub-slice of the encoded data in the range [from, to), which can be used to access
+// the raw bytes of fields that have already been read or skipped.  An error is returned if the range
+// is not within the bounds of the data.
+//
+// As with Buffer(), the returned slice is not a copy and should be treated as read-only.
+func (d *Decoder) Slice(from, to int) ([]byte, error) {
+	if from < 0 || to < from || to > len(d.p) {
+		return nil, fmt.Errorf("slice range [%d:%d] out of bounds", from, to)
+	}
+	return d.p[from:to:to], nil
+}
+
+// checkRead returns an error if at least n bytes cannot be read from the current offset, or if the
+// data exceeds the configured maximum message size.
+func (d *Decoder) checkRead(n int) error {
+	if d.maxSize > 0 && len(d.p) > d.maxSize {
+		return fmt.Errorf("%w: %d bytes is larger than the limit of %d bytes", ErrMessageTooLarge, len(d.p), d.maxSize)
+	}
+	if len(d.p)-d.offset < n {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
 // DecodeTag decodes a field tag and Protobuf wire type from the stream and returns the values.
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeTag() (tag int, wireType WireType, err error) {
-	if d.offset >= len(d.p) {
-		return 0, WireTypeVarint, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, WireTypeVarint, err
 	}
 	v, n, err := DecodeVarint(d.p[d.offset:])
 	if err != nil {
@@ -138,16 +253,43 @@ func (d *Decoder) DecodeTag() (tag int, wireType WireType, err error) {
 	if n < 1 || v < 1 || v > MaxTagValue {
 		return 0, -1, fmt.Errorf("invalid tag value (%d) at byte %d: %w", v, d.offset, ErrInvalidFieldTag)
 	}
+	if wt := WireType(v & 0x7); d.strictWireTypes && (wt == WireTypeStartGroup || wt == WireTypeEndGroup) {
+		return 0, -1, fmt.Errorf("invalid wire type (%s) for tag %d at byte %d: %w", wt, v>>3, d.offset, ErrDeprecatedWireType)
+	}
 	d.offset += n
 	return int(v >> 3), WireType(v & 0x7), nil
 }
 
+// DecodeTagInfo decodes a field tag and Protobuf wire type from the stream and returns them as a
+// TagInfo value.  It is equivalent to DecodeTag() but is more convenient when the tag and wire type
+// are passed around together.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodeTagInfo() (TagInfo, error) {
+	tag, wt, err := d.DecodeTag()
+	if err != nil {
+		return TagInfo{}, err
+	}
+	return TagInfo{Tag: tag, WireType: wt}, nil
+}
+
+// Peek decodes the next field tag and Protobuf wire type from the stream without advancing the read
+// offset, which allows custom decode loops to look ahead and decide whether to decode or skip the field.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) Peek() (tag int, wireType WireType, err error) {
+	offset := d.offset
+	tag, wireType, err = d.DecodeTag()
+	d.offset = offset
+	return tag, wireType, err
+}
+
 // DecodeBool decodes a boolean value from the stream and returns the value.
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeBool() (b bool, err error) {
-	if d.offset >= len(d.p) {
-		return false, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return false, err
 	}
 	v, n, err := DecodeVarint(d.p[d.offset:])
 	if err != nil {
@@ -164,8 +306,8 @@ func (d *Decoder) DecodeBool() (b bool, err error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeString() (string, error) {
-	if d.offset >= len(d.p) {
-		return "", io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return "", err
 	}
 	b, err := d.DecodeBytes()
 	if err != nil {
@@ -185,8 +327,8 @@ func (d *Decoder) DecodeString() (string, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeBytes() ([]byte, error) {
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 
 	l, n, err := DecodeVarint(d.p[d.offset:])
@@ -214,8 +356,8 @@ func (d *Decoder) DecodeBytes() ([]byte, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeUInt32() (uint32, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeVarint(d.p[d.offset:])
 	if err != nil {
@@ -235,8 +377,8 @@ func (d *Decoder) DecodeUInt32() (uint32, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeUInt64() (uint64, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeVarint(d.p[d.offset:])
 	if err != nil {
@@ -253,8 +395,8 @@ func (d *Decoder) DecodeUInt64() (uint64, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeInt32() (int32, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeVarint(d.p[d.offset:])
 	if err != nil {
@@ -275,8 +417,8 @@ func (d *Decoder) DecodeInt32() (int32, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeInt64() (int64, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeVarint(d.p[d.offset:])
 	if err != nil {
@@ -293,8 +435,8 @@ func (d *Decoder) DecodeInt64() (int64, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeSInt32() (int32, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeZigZag32(d.p[d.offset:])
 	if err != nil {
@@ -311,8 +453,8 @@ func (d *Decoder) DecodeSInt32() (int32, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeSInt64() (int64, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeZigZag64(d.p[d.offset:])
 	if err != nil {
@@ -325,12 +467,20 @@ func (d *Decoder) DecodeSInt64() (int64, error) {
 	return v, nil
 }
 
+// DecodeEnum decodes a varint-encoded enum value from the stream and returns the value.  Enum values
+// are encoded the same as 32-bit signed integers.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodeEnum() (int32, error) {
+	return d.DecodeInt32()
+}
+
 // DecodeFixed32 decodes a 4-byte integer from the stream and returns the value.
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeFixed32() (uint32, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeFixed32(d.p[d.offset:])
 	if err != nil {
@@ -347,8 +497,8 @@ func (d *Decoder) DecodeFixed32() (uint32, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeFixed64() (uint64, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return 0, err
 	}
 	v, n, err := DecodeFixed64(d.p[d.offset:])
 	if err != nil {
@@ -361,12 +511,28 @@ func (d *Decoder) DecodeFixed64() (uint64, error) {
 	return v, nil
 }
 
+// DecodeSFixed32 decodes a 4-byte signed integer from the stream and returns the value.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodeSFixed32() (int32, error) {
+	v, err := d.DecodeFixed32()
+	return int32(v), err
+}
+
+// DecodeSFixed64 decodes an 8-byte signed integer from the stream and returns the value.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodeSFixed64() (int64, error) {
+	v, err := d.DecodeFixed64()
+	return int64(v), err
+}
+
 // DecodeFloat32 decodes a 4-byte IEEE 754 floating point value from the stream and returns the value.
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeFloat32() (float32, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(4); err != nil {
+		return 0, err
 	}
 	v := binary.LittleEndian.Uint32(d.p[d.offset:])
 	fv := math.Float32frombits(v)
@@ -378,8 +544,8 @@ func (d *Decoder) DecodeFloat32() (float32, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeFloat64() (float64, error) {
-	if d.offset >= len(d.p) {
-		return 0, io.ErrUnexpectedEOF
+	if err := d.checkRead(8); err != nil {
+		return 0, err
 	}
 	v := binary.LittleEndian.Uint64(d.p[d.offset:])
 	fv := math.Float64frombits(v)
@@ -391,8 +557,8 @@ func (d *Decoder) DecodeFloat64() (float64, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedBool() ([]bool, error) {
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -434,8 +600,8 @@ func (d *Decoder) DecodePackedBool() ([]bool, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedInt32() ([]int32, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -464,7 +630,7 @@ func (d *Decoder) DecodePackedInt32() ([]int32, error) { //nolint: dupl // FALSE
 			return nil, fmt.Errorf("invalid data at byte %d: %w", d.offset, ErrInvalidVarintData)
 		}
 		// ensure the result is within [-math.MaxInt32, math.MaxInt32] when converted to a signed value
-		if v > math.MaxInt32 {
+		if i64 := int64(v); i64 > math.MaxInt32 || i64 < math.MinInt32 {
 			return nil, fmt.Errorf("invalid data at byte %d: %w", d.offset, ErrValueOverflow)
 		}
 		nRead += uint64(n)
@@ -481,8 +647,8 @@ func (d *Decoder) DecodePackedInt32() ([]int32, error) { //nolint: dupl // FALSE
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedInt64() ([]int64, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -528,8 +694,8 @@ func (d *Decoder) DecodePackedInt64() ([]int64, error) { //nolint: dupl // FALSE
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedUint32() ([]uint32, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -576,8 +742,8 @@ func (d *Decoder) DecodePackedUint32() ([]uint32, error) { //nolint: dupl // FAL
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedUint64() ([]uint64, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -620,8 +786,8 @@ func (d *Decoder) DecodePackedUint64() ([]uint64, error) { //nolint: dupl // FAL
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedSint32() ([]int32, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -664,8 +830,8 @@ func (d *Decoder) DecodePackedSint32() ([]int32, error) { //nolint: dupl // FALS
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedSint64() ([]int64, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -703,13 +869,20 @@ func (d *Decoder) DecodePackedSint64() ([]int64, error) { //nolint: dupl // FALS
 	return res, nil
 }
 
+// DecodePackedEnum decodes a packed encoded list of enum values from the stream and returns the value.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodePackedEnum() ([]int32, error) {
+	return d.DecodePackedInt32()
+}
+
 // DecodePackedFixed32 decodes a packed encoded list of 32-bit fixed-width integers from the stream
 // and returns the value.
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedFixed32() ([]uint32, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -752,8 +925,8 @@ func (d *Decoder) DecodePackedFixed32() ([]uint32, error) { //nolint: dupl // FA
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedFixed64() ([]uint64, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -791,13 +964,45 @@ func (d *Decoder) DecodePackedFixed64() ([]uint64, error) { //nolint: dupl // FA
 	return res, nil
 }
 
+// DecodePackedSFixed32 decodes a packed encoded list of 32-bit signed fixed-width integers from the
+// stream and returns the value.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodePackedSFixed32() ([]int32, error) {
+	vs, err := d.DecodePackedFixed32()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int32, len(vs))
+	for i, v := range vs {
+		res[i] = int32(v)
+	}
+	return res, nil
+}
+
+// DecodePackedSFixed64 decodes a packed encoded list of 64-bit signed fixed-width integers from the
+// stream and returns the value.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodePackedSFixed64() ([]int64, error) {
+	vs, err := d.DecodePackedFixed64()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, len(vs))
+	for i, v := range vs {
+		res[i] = int64(v)
+	}
+	return res, nil
+}
+
 // DecodePackedFloat32 decodes a packed encoded list of 32-bit floating point numbers from the stream
 // and returns the value.
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedFloat32() ([]float32, error) { //nolint: dupl // FALSE POSITIVE: this function is NOT a duplicate
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -835,8 +1040,8 @@ func (d *Decoder) DecodePackedFloat32() ([]float32, error) { //nolint: dupl // F
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodePackedFloat64() ([]float64, error) {
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	var (
 		l, nRead uint64
@@ -873,8 +1078,8 @@ func (d *Decoder) DecodePackedFloat64() ([]float64, error) {
 //
 // io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
 func (d *Decoder) DecodeNested(m interface{}) error {
-	if d.offset >= len(d.p) {
-		return io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return err
 	}
 
 	l, n, err := DecodeVarint(d.p[d.offset:])
@@ -918,8 +1123,8 @@ func (d *Decoder) DecodeNested(m interface{}) error {
 //
 // io.ErrUnexpectedEOF is returned if the operation would advance past the end of the data.
 func (d *Decoder) Skip(tag int, wt WireType) ([]byte, error) {
-	if d.offset >= len(d.p) {
-		return nil, io.ErrUnexpectedEOF
+	if err := d.checkRead(1); err != nil {
+		return nil, err
 	}
 	sz := SizeOfTagKey(tag)
 	bof := d.offset - sz
@@ -974,6 +1179,12 @@ func (d *Decoder) Skip(tag int, wt WireType) ([]byte, error) {
 
 	case WireTypeFixed32:
 		skipped = 4
+	case WireTypeStartGroup:
+		_, next, err := scanGroup(d.p, d.offset, tag)
+		if err != nil {
+			return nil, err
+		}
+		skipped = next - d.offset
 	default:
 		return nil, fmt.Errorf("unsupported wire type value %v at byte %d", wt, d.offset)
 	}
@@ -984,6 +1195,202 @@ func (d *Decoder) Skip(tag int, wt WireType) ([]byte, error) {
 	return d.p[bof:d.offset], nil
 }
 
+// DecodeRawField reads the next field tag and Protobuf wire type along with the entire encoded value in
+// a single call, which avoids having to pass the results of DecodeTag() to Skip().  The returned raw
+// bytes do not include the tag key.  For WireTypeLengthDelimited, they are the content of the field
+// without the varint length prefix.  For groups, they are the encoded group contents followed by the
+// closing WireTypeEndGroup tag.  For all other wire types, they are the encoded value as it appears in
+// the data.  This matches the format expected by Encoder.EncodeRawField(), so fields can be copied
+// verbatim from one message to another.
+//
+// When using DecoderModeFast, the returned slice refers to the decoder's buffer.  Otherwise, it is a copy.
+// If an error occurs, the read offset is not changed.
+//
+// io.ErrUnexpectedEOF is returned if the operation would read past the end of the data.
+func (d *Decoder) DecodeRawField() (tag int, wt WireType, raw []byte, err error) {
+	start := d.offset
+	tag, wt, err = d.DecodeTag()
+	if err != nil {
+		return 0, -1, nil, err
+	}
+	if wt == WireTypeLengthDelimited {
+		raw, err = d.DecodeBytes()
+	} else {
+		valueStart := d.offset
+		if _, err = d.Skip(tag, wt); err == nil {
+			raw = d.p[valueStart:d.offset]
+		}
+	}
+	if err != nil {
+		d.offset = start
+		return 0, -1, nil, err
+	}
+	if d.mode == DecoderModeSafe {
+		raw = append([]byte(nil), raw...)
+	}
+	return tag, wt, raw, nil
+}
+
+// ForEach calls fn for each remaining field in the data, in order, passing the field tag, Protobuf wire
+// type, and raw value bytes as returned by DecodeRawField().  If fn returns an error, iteration stops and
+// that error is returned.
+func (d *Decoder) ForEach(fn func(tag int, wt WireType, raw []byte) error) error {
+	for d.More() {
+		tag, wt, raw, err := d.DecodeRawField()
+		if err != nil {
+			return err
+		}
+		if err = fn(tag, wt, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeGroup reads a deprecated proto2 group from the stream and returns the raw bytes of the group
+// contents, which can be passed to NewDecoder() to read the fields within the group.  The start-group
+// tag must have already been consumed by DecodeTag() and the group is terminated by the first end-group
+// tag with the same field number.  Nested groups are skipped over as a unit.
+//
+// io.ErrUnexpectedEOF is returned if the end of the data is reached before the end of the group and
+// ErrInvalidGroupData is returned if an end-group tag for a different field is encountered or if the
+// groups are nested more than 10,000 levels deep.
+func (d *Decoder) DecodeGroup(tag int) ([]byte, error) {
+	if err := d.checkRead(1); err != nil {
+		return nil, err
+	}
+	end, next, err := scanGroup(d.p, d.offset, tag)
+	if err != nil {
+		return nil, err
+	}
+	b := d.p[d.offset:end]
+	d.offset = next
+	return b, nil
+}
+
+// maxGroupDepth is the maximum nesting depth of groups supported by Skip() and DecodeGroup(), which
+// matches the default recursion limit of the Google Protobuf runtime.
+const maxGroupDepth = 10000
+
+// scanGroup reads the fields of a group with the specified tag that starts at offset in p, returning
+// the offset of the terminating end-group tag and the offset of the first byte after it.
+//
+// Nested groups are tracked using a stack rather than recursion so that malicious data cannot exhaust
+// the call stack.  ErrInvalidGroupData is returned if groups are nested more than maxGroupDepth deep.
+func scanGroup(p []byte, offset int, tag int) (end, next int, err error) {
+	// the field numbers of the groups that are currently open, innermost last
+	open := []int{tag}
+	for offset < len(p) {
+		fieldStart := offset
+		k, n, err := DecodeVarint(p[offset:])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid data at byte %d: %w", offset, err)
+		}
+		offset += n
+		fieldTag, wt := int(k>>3), WireType(k&0x7)
+		switch wt {
+		case WireTypeVarint:
+			_, n, err := DecodeVarint(p[offset:])
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid data at byte %d: %w", offset, err)
+			}
+			offset += n
+		case WireTypeFixed64:
+			offset += 8
+		case WireTypeLengthDelimited:
+			l, n, err := DecodeVarint(p[offset:])
+			switch {
+			case err != nil:
+				return 0, 0, fmt.Errorf("invalid data at byte %d: %w", offset, err)
+			case l > maxFieldLen:
+				return 0, 0, fmt.Errorf("invalid length (%d) for length-delimited field at byte %d: %w", l, offset, ErrLenOverflow)
+			default:
+				// length is good
+			}
+			offset += n + int(l)
+		case WireTypeFixed32:
+			offset += 4
+		case WireTypeStartGroup:
+			if len(open) >= maxGroupDepth {
+				return 0, 0, fmt.Errorf("groups are nested more than %d levels deep at byte %d: %w", maxGroupDepth, fieldStart, ErrInvalidGroupData)
+			}
+			open = append(open, fieldTag)
+		case WireTypeEndGroup:
+			if current := open[len(open)-1]; fieldTag != current {
+				return 0, 0, fmt.Errorf("unexpected end of group %d at byte %d while reading group %d: %w", fieldTag, fieldStart, current, ErrInvalidGroupData)
+			}
+			open = open[:len(open)-1]
+			if len(open) == 0 {
+				return fieldStart, offset, nil
+			}
+		default:
+			return 0, 0, fmt.Errorf("unsupported wire type value %v at byte %d", wt, fieldStart)
+		}
+	}
+	return 0, 0, io.ErrUnexpectedEOF
+}
+
+// Validate scans the entire buffer and verifies that it contains well-formed Protobuf binary data
+// without decoding any values.  The current read offset is not changed.
+//
+// The data is valid if every field has a tag in the range [1, MaxTagValue] and a supported wire type,
+// and the value of each field fits within the remaining data.  The contents of length-delimited fields
+// are not inspected because the encoded data does not indicate whether they hold a nested message, a
+// string, or packed values.
+//
+// A *ValidationError is returned for the first problem found.
+func (d *Decoder) Validate() error {
+	for offset := 0; offset < len(d.p); {
+		k, n, err := DecodeVarint(d.p[offset:])
+		if err != nil {
+			return &ValidationError{Offset: offset, Reason: fmt.Sprintf("unable to read field tag: %v", err)}
+		}
+		tag, wt := k>>3, WireType(k&0x7)
+		if tag < 1 || tag > MaxTagValue {
+			return &ValidationError{Offset: offset, Reason: fmt.Sprintf("invalid field tag %d", tag)}
+		}
+		offset += n
+		switch wt {
+		case WireTypeVarint:
+			_, n, err := DecodeVarint(d.p[offset:])
+			if err != nil {
+				return &ValidationError{Offset: offset, Reason: fmt.Sprintf("unable to read varint value for tag %d: %v", tag, err)}
+			}
+			offset += n
+		case WireTypeFixed64:
+			if len(d.p)-offset < 8 {
+				return &ValidationError{Offset: offset, Reason: fmt.Sprintf("not enough data for fixed64 value for tag %d", tag)}
+			}
+			offset += 8
+		case WireTypeLengthDelimited:
+			l, n, err := DecodeVarint(d.p[offset:])
+			switch {
+			case err != nil:
+				return &ValidationError{Offset: offset, Reason: fmt.Sprintf("unable to read length for tag %d: %v", tag, err)}
+			case l > maxFieldLen:
+				return &ValidationError{Offset: offset, Reason: fmt.Sprintf("invalid length (%d) for tag %d: %v", l, tag, ErrLenOverflow)}
+			case int(l) > len(d.p)-offset-n:
+				return &ValidationError{Offset: offset, Reason: fmt.Sprintf("length (%d) for tag %d exceeds the remaining data", l, tag)}
+			default:
+				// length is good
+			}
+			offset += n + int(l)
+		case WireTypeFixed32:
+			if len(d.p)-offset < 4 {
+				return &ValidationError{Offset: offset, Reason: fmt.Sprintf("not enough data for fixed32 value for tag %d", tag)}
+			}
+			offset += 4
+		default:
+			// wire types 3 and 4 are the start/end markers for deprecated proto2 groups, which have no
+			// value of their own, so the only invalid values are 6 and 7
+			if wt > 5 {
+				return &ValidationError{Offset: offset - n, Reason: fmt.Sprintf("invalid wire type %d for tag %d", int(wt), tag)}
+			}
+		}
+	}
+	return nil
+}
+
 // DecodeVarint reads a base-128 [varint encoded] integer from p and returns the value and the number
 // of bytes that were consumed.
 //
@@ -1105,3 +1512,38 @@ type DecoderSkipError struct {
 func (e *DecoderSkipError) Error() string {
 	return fmt.Sprintf("unexpected tag/wire type (%d, %s), expected (%d, %s)", e.ActualTag, e.ActualWireType, e.ExpectedTag, e.ExpectedWireType)
 }
+
+// TagInfo holds a field tag and Protobuf wire type read by the decoder's DecodeTagInfo() method.
+type TagInfo struct {
+	Tag      int
+	WireType WireType
+}
+
+// IsVarint returns true if the field uses the varint wire type.
+func (ti TagInfo) IsVarint() bool {
+	return ti.WireType == WireTypeVarint
+}
+
+// IsFixed returns true if the field uses either the fixed32 or fixed64 wire type.
+func (ti TagInfo) IsFixed() bool {
+	return ti.WireType == WireTypeFixed32 || ti.WireType == WireTypeFixed64
+}
+
+// IsLengthDelimited returns true if the field uses the length-delimited wire type.
+func (ti TagInfo) IsLengthDelimited() bool {
+	return ti.WireType == WireTypeLengthDelimited
+}
+
+// ValidationError defines an error returned by the decoder's Validate() method when the data is not
+// well-formed Protobuf binary data.
+type ValidationError struct {
+	// Offset is the position in the data where the problem was found
+	Offset int
+	// Reason describes the problem
+	Reason string
+}
+
+// Error satisfies the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid protobuf data at byte %d: %s", e.Offset, e.Reason)
+}
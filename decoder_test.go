@@ -1,6 +1,7 @@
 package csproto_test
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"math"
@@ -906,6 +907,57 @@ func TestDecodePackedInt32(t *testing.T) {
 	assert.ElementsMatch(t, vals, []int32{3, 270, 86942}, "slice values should match")
 }
 
+func TestDecodePackedInt32NegativeValues(t *testing.T) {
+	var (
+		data = []byte{
+			// tag=4, wire type=2
+			0x22,
+			// total length (21)
+			0x15,
+			// varint 42
+			0x2A,
+			// varint -42, sign-extended to 10 bytes
+			0xD6, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01,
+			// varint math.MinInt32, sign-extended to 10 bytes
+			0x80, 0x80, 0x80, 0x80, 0xF8, 0xFF, 0xFF, 0xFF, 0xFF, 0x01,
+		}
+	)
+	dec := csproto.NewDecoder(data)
+	tag, wt, err := dec.DecodeTag()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, tag, "tag should match")
+	assert.Equal(t, csproto.WireTypeLengthDelimited, wt, "wire type should match")
+
+	vals, err := dec.DecodePackedInt32()
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{42, -42, math.MinInt32}, vals, "slice values should match")
+}
+
+func TestDecodeInt32NegativeValueRoundTrip(t *testing.T) {
+	vs := []int32{-42, math.MinInt32}
+	// field 1: 1 byte tag + 10 byte varint
+	// field 2: 1 byte tag + 1 byte length + 2 10-byte varints
+	buf := make([]byte, 11+22)
+	enc := csproto.NewEncoder(buf)
+	enc.EncodeInt32(1, -42)
+	enc.EncodePackedInt32(2, vs)
+	// negative int32 values are always encoded as 10-byte varints
+	assert.Equal(t, []byte{0x08, 0xD6, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}, buf[:11])
+
+	dec := csproto.NewDecoder(buf)
+	_, _, err := dec.DecodeTag()
+	assert.NoError(t, err)
+	v, err := dec.DecodeInt32()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(-42), v)
+
+	_, _, err = dec.DecodeTag()
+	assert.NoError(t, err)
+	got, err := dec.DecodePackedInt32()
+	assert.NoError(t, err)
+	assert.Equal(t, vs, got)
+}
+
 func TestDecodePackedInt64(t *testing.T) {
 	var (
 		data = []byte{
@@ -1239,6 +1291,135 @@ func TestDecoderInvalidSkip(t *testing.T) {
 	})
 }
 
+func TestDecoderDecodeGroup(t *testing.T) {
+	var data = []byte{
+		// 2 (start group)
+		0x13,
+		// 1 (varint): 42
+		0x8, 0x2A,
+		// 3 (start group)
+		0x1B,
+		// 1 (varint): 1
+		0x8, 0x1,
+		// 3 (end group)
+		0x1C,
+		// 2 (end group)
+		0x14,
+		// 4 (varint): 7
+		0x20, 0x7,
+	}
+
+	dec := csproto.NewDecoder(data)
+	tag, wt, err := dec.DecodeTag()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tag)
+	assert.Equal(t, csproto.WireTypeStartGroup, wt)
+
+	group, err := dec.DecodeGroup(tag)
+	assert.NoError(t, err)
+	assert.Equal(t, data[1:7], group, "group content should include the nested group")
+
+	// the group contents can be read with a separate decoder
+	gdec := csproto.NewDecoder(group)
+	tag, wt, _ = gdec.DecodeTag()
+	assert.Equal(t, 1, tag)
+	assert.Equal(t, csproto.WireTypeVarint, wt)
+	v, _ := gdec.DecodeInt32()
+	assert.Equal(t, int32(42), v)
+	tag, wt, _ = gdec.DecodeTag()
+	assert.Equal(t, 3, tag)
+	assert.Equal(t, csproto.WireTypeStartGroup, wt)
+	nested, err := gdec.DecodeGroup(tag)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x8, 0x1}, nested)
+	assert.False(t, gdec.More())
+
+	// the decoder should be positioned after the end group tag
+	tag, _, _ = dec.DecodeTag()
+	assert.Equal(t, 4, tag)
+	v, _ = dec.DecodeInt32()
+	assert.Equal(t, int32(7), v)
+	assert.False(t, dec.More())
+
+	t.Run("empty group", func(t *testing.T) {
+		dec := csproto.NewDecoder([]byte{0x13, 0x14})
+		tag, _, _ := dec.DecodeTag()
+		group, err := dec.DecodeGroup(tag)
+		assert.NoError(t, err)
+		assert.Empty(t, group)
+		assert.False(t, dec.More())
+	})
+	t.Run("missing end group", func(t *testing.T) {
+		dec := csproto.NewDecoder([]byte{0x13, 0x8, 0x2A})
+		tag, _, _ := dec.DecodeTag()
+		_, err := dec.DecodeGroup(tag)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+	t.Run("mismatched end group", func(t *testing.T) {
+		dec := csproto.NewDecoder([]byte{0x13, 0x8, 0x2A, 0x1C})
+		tag, _, _ := dec.DecodeTag()
+		_, err := dec.DecodeGroup(tag)
+		assert.ErrorIs(t, err, csproto.ErrInvalidGroupData)
+	})
+}
+
+func TestDecoderSkipGroup(t *testing.T) {
+	var data = []byte{
+		// 1 (varint): 42
+		0x8, 0x2A,
+		// 2 (start group)
+		0x13,
+		// 1 (length-delimited): "hi"
+		0xA, 0x2, 0x68, 0x69,
+		// 3 (start group), 5 (fixed32): 1, 3 (end group)
+		0x1B, 0x2D, 0x1, 0x0, 0x0, 0x0, 0x1C,
+		// 2 (end group)
+		0x14,
+		// 4 (varint): 7
+		0x20, 0x7,
+	}
+
+	dec := csproto.NewDecoder(data)
+	_, _, _ = dec.DecodeTag()
+	_, _ = dec.DecodeInt32()
+	tag, wt, _ := dec.DecodeTag()
+	skipped, err := dec.Skip(tag, wt)
+	assert.NoError(t, err)
+	assert.Equal(t, data[2:15], skipped, "skipped data should include the start and end group tags")
+
+	tag, _, _ = dec.DecodeTag()
+	assert.Equal(t, 4, tag)
+	v, _ := dec.DecodeInt32()
+	assert.Equal(t, int32(7), v)
+}
+
+func TestDecoderSkipDeeplyNestedGroups(t *testing.T) {
+	t.Run("within the limit", func(t *testing.T) {
+		// 1000 nested groups, all with tag 1
+		data := append(bytes.Repeat([]byte{0x0B}, 1000), bytes.Repeat([]byte{0x0C}, 1000)...)
+		dec := csproto.NewDecoder(data)
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		skipped, err := dec.Skip(tag, wt)
+		assert.NoError(t, err)
+		assert.Equal(t, data, skipped)
+		assert.False(t, dec.More())
+	})
+	t.Run("exceeds the limit", func(t *testing.T) {
+		// ~2MB of start group tags, which should be rejected rather than overflowing the stack
+		data := bytes.Repeat([]byte{0x0B}, 2<<20)
+		dec := csproto.NewDecoder(data)
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		_, err = dec.Skip(tag, wt)
+		assert.ErrorIs(t, err, csproto.ErrInvalidGroupData)
+		assert.Equal(t, 1, dec.Offset(), "the read offset should not change on error")
+
+		_, err = dec.DecodeGroup(tag)
+		assert.ErrorIs(t, err, csproto.ErrInvalidGroupData)
+	})
+}
+
 func TestDecodePastEndOfBuffer(t *testing.T) {
 	var data = []byte{
 		// 1 (varint): 42
@@ -1308,6 +1489,29 @@ func TestDecodePastEndOfBuffer(t *testing.T) {
 	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "Skip() should return io.ErrUnexpectedEOF")
 }
 
+func TestDecodeFloatShortBuffer(t *testing.T) {
+	t.Parallel()
+	var data = []byte{
+		// 1 (fixed32): truncated to 3 bytes
+		(1 << 3) | 5, 0x00, 0x00, 0x80,
+	}
+	dec := csproto.NewDecoder(data)
+	_, _, _ = dec.DecodeTag()
+	_, err := dec.DecodeFloat32()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "DecodeFloat32() should return io.ErrUnexpectedEOF")
+	assert.Equal(t, 1, dec.Offset(), "the decoder should not advance")
+
+	data = []byte{
+		// 1 (fixed64): truncated to 7 bytes
+		(1 << 3) | 1, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0,
+	}
+	dec = csproto.NewDecoder(data)
+	_, _, _ = dec.DecodeTag()
+	_, err = dec.DecodeFloat64()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "DecodeFloat64() should return io.ErrUnexpectedEOF")
+	assert.Equal(t, 1, dec.Offset(), "the decoder should not advance")
+}
+
 func TestDecodeTag(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
@@ -1322,12 +1526,22 @@ func TestDecodeTag(t *testing.T) {
 			data: []byte{0x80},
 			err:  io.ErrUnexpectedEOF,
 		},
+		{
+			name: "truncated 9-byte varint",
+			data: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+			err:  io.ErrUnexpectedEOF,
+		},
 		{
 			name: "varint overflow",
 			// 11 bytes == overflow
 			data: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
 			err:  csproto.ErrValueOverflow,
 		},
+		{
+			name: "10-byte varint without a stop bit",
+			data: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+			err:  csproto.ErrValueOverflow,
+		},
 	}
 	for _, tc := range cases {
 		tc := tc
@@ -1348,6 +1562,67 @@ func TestDecodeTag(t *testing.T) {
 	}
 }
 
+func TestDecodeTagInfo(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name              string
+		data              []byte
+		expected          csproto.TagInfo
+		isVarint          bool
+		isFixed           bool
+		isLengthDelimited bool
+	}{
+		{
+			name:     "varint",
+			data:     []byte{(1 << 3)},
+			expected: csproto.TagInfo{Tag: 1, WireType: csproto.WireTypeVarint},
+			isVarint: true,
+		},
+		{
+			name:     "fixed64",
+			data:     []byte{(2 << 3) | 1},
+			expected: csproto.TagInfo{Tag: 2, WireType: csproto.WireTypeFixed64},
+			isFixed:  true,
+		},
+		{
+			name:              "length-delimited",
+			data:              []byte{(3 << 3) | 2},
+			expected:          csproto.TagInfo{Tag: 3, WireType: csproto.WireTypeLengthDelimited},
+			isLengthDelimited: true,
+		},
+		{
+			name:     "fixed32",
+			data:     []byte{(4 << 3) | 5},
+			expected: csproto.TagInfo{Tag: 4, WireType: csproto.WireTypeFixed32},
+			isFixed:  true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ti, err := csproto.NewDecoder(tc.data).DecodeTagInfo()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ti)
+			assert.Equal(t, tc.isVarint, ti.IsVarint())
+			assert.Equal(t, tc.isFixed, ti.IsFixed())
+			assert.Equal(t, tc.isLengthDelimited, ti.IsLengthDelimited())
+
+			// should match DecodeTag()
+			tag, wt, err := csproto.NewDecoder(tc.data).DecodeTag()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.Tag, tag)
+			assert.Equal(t, tc.expected.WireType, wt)
+		})
+	}
+	t.Run("empty data", func(t *testing.T) {
+		t.Parallel()
+		ti, err := csproto.NewDecoder(nil).DecodeTagInfo()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, csproto.TagInfo{}, ti)
+	})
+}
+
 func FuzzDecodeTag(f *testing.F) {
 	seedData := [][]byte{
 		{(1 << 3)},     // tag=1, wire type=0
@@ -1365,6 +1640,13 @@ func FuzzDecodeTag(f *testing.F) {
 		csproto.EncodeTag(d, csproto.MaxTagValue, csproto.WireType(v))
 		seedData = append(seedData, d)
 	}
+	// add seed data for truncated and overflowed varints
+	seedData = append(seedData,
+		[]byte{0x80},
+		[]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+		[]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+		[]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+	)
 	for _, s := range seedData {
 		f.Add(s)
 	}
@@ -1387,3 +1669,438 @@ func FuzzDecodeTag(f *testing.F) {
 		}
 	})
 }
+
+func TestDecoderValidate(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name      string
+		data      []byte
+		expectErr bool
+		offset    int
+	}{
+		{name: "empty buffer", data: []byte{}},
+		{name: "varint field", data: []byte{0x08, 0x96, 0x01}},
+		{name: "fixed64 field", data: []byte{0x09, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8}},
+		{name: "fixed32 field", data: []byte{0x0D, 0x1, 0x2, 0x3, 0x4}},
+		{name: "length-delimited field", data: []byte{0x12, 0x03, 'f', 'o', 'o'}},
+		{name: "multiple fields", data: []byte{0x08, 0x01, 0x12, 0x01, 'a', 0x1D, 0x1, 0x2, 0x3, 0x4}},
+		{name: "group markers", data: []byte{0x0B, 0x10, 0x01, 0x0C}},
+		{name: "zero tag", data: []byte{0x00, 0x01}, expectErr: true, offset: 0},
+		{name: "invalid wire type", data: []byte{0x08, 0x01, 0x0E, 0x01}, expectErr: true, offset: 2},
+		{name: "truncated tag", data: []byte{0x08, 0x01, 0x80}, expectErr: true, offset: 2},
+		{name: "truncated varint value", data: []byte{0x08, 0x96}, expectErr: true, offset: 1},
+		{name: "truncated fixed64 value", data: []byte{0x09, 0x1, 0x2, 0x3}, expectErr: true, offset: 1},
+		{name: "truncated fixed32 value", data: []byte{0x0D, 0x1, 0x2}, expectErr: true, offset: 1},
+		{name: "length past end of buffer", data: []byte{0x12, 0x05, 'f', 'o', 'o'}, expectErr: true, offset: 1},
+		{name: "length overflow", data: []byte{0x12, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F}, expectErr: true, offset: 1},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dec := csproto.NewDecoder(tc.data)
+			err := dec.Validate()
+			assert.Equal(t, 0, dec.Offset(), "Validate() should not change the decoder offset")
+			if !tc.expectErr {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *csproto.ValidationError
+			if assert.ErrorAs(t, err, &verr) {
+				assert.Equal(t, tc.offset, verr.Offset)
+				assert.NotEmpty(t, verr.Reason)
+			}
+		})
+	}
+}
+
+func FuzzDecoderValidate(f *testing.F) {
+	seedData := [][]byte{
+		{0x08, 0x96, 0x01},
+		{0x09, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8},
+		{0x0D, 0x1, 0x2, 0x3, 0x4},
+		{0x12, 0x03, 'f', 'o', 'o'},
+		{0x0B, 0x10, 0x01, 0x0C},
+		{0x12, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F},
+	}
+	for _, s := range seedData {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, d []byte) {
+		dec := csproto.NewDecoder(d)
+		err := dec.Validate()
+		if dec.Offset() != 0 {
+			t.Errorf("Validate() moved the decoder offset to %d", dec.Offset())
+		}
+		if err == nil {
+			return
+		}
+		var verr *csproto.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("unexpected error type from Validate(): %T (%v)", err, err)
+		}
+		if verr.Offset < 0 || verr.Offset > len(d) {
+			t.Errorf("invalid error offset %d for data of length %d", verr.Offset, len(d))
+		}
+	})
+}
+
+func TestDecoderRemainingAndTotal(t *testing.T) {
+	testData := []byte{0x08, 0x01, 0x10, 0x00, 0x1A, 0xE, 0x74, 0x68, 0x69, 0x73, 0x20, 0x69, 0x73, 0x20, 0x61, 0x20, 0x74, 0x65, 0x73, 0x74}
+	dec := csproto.NewDecoder(testData)
+
+	assert.Equal(t, len(testData), dec.Total())
+	assert.Equal(t, len(testData), dec.Remaining())
+	for dec.More() {
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		assert.Equal(t, dec.Total(), dec.Remaining()+dec.Offset())
+
+		_, err = dec.Skip(tag, wt)
+		assert.NoError(t, err)
+		assert.Equal(t, dec.Total(), dec.Remaining()+dec.Offset())
+	}
+	assert.Equal(t, 0, dec.Remaining(), "all data should have been read")
+	assert.Equal(t, len(testData), dec.Total(), "total should not change")
+
+	dec.Reset()
+	assert.Equal(t, len(testData), dec.Remaining())
+
+	empty := csproto.NewDecoder(nil)
+	assert.Equal(t, 0, empty.Remaining())
+	assert.Equal(t, 0, empty.Total())
+}
+
+func TestDecoderAtEnd(t *testing.T) {
+	testData := []byte{0x08, 0x01, 0x10, 0x00, 0x1A, 0xE, 0x74, 0x68, 0x69, 0x73, 0x20, 0x69, 0x73, 0x20, 0x61, 0x20, 0x74, 0x65, 0x73, 0x74}
+	dec := csproto.NewDecoder(testData)
+
+	assert.False(t, dec.AtEnd())
+	fields := 0
+	for !dec.AtEnd() {
+		assert.Equal(t, !dec.More(), dec.AtEnd())
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		assert.Equal(t, !dec.More(), dec.AtEnd())
+
+		_, err = dec.Skip(tag, wt)
+		assert.NoError(t, err)
+		assert.Equal(t, !dec.More(), dec.AtEnd())
+		fields++
+	}
+	assert.Equal(t, 3, fields)
+	assert.True(t, dec.AtEnd())
+
+	dec.Reset()
+	assert.False(t, dec.AtEnd())
+
+	empty := csproto.NewDecoder(nil)
+	assert.True(t, empty.AtEnd())
+	assert.False(t, empty.More())
+}
+
+func TestDecoderResetWithBuffer(t *testing.T) {
+	msgs := [][]byte{
+		// 1 (varint): 1
+		{0x08, 0x01},
+		// 1 (varint): 150
+		{0x08, 0x96, 0x01},
+		// 2 (length-delimited): "test"
+		{0x12, 0x04, 't', 'e', 's', 't'},
+	}
+	dec := csproto.NewDecoder(nil, csproto.WithMode(csproto.DecoderModeFast))
+	assert.False(t, dec.More())
+
+	var tags []int
+	for _, msg := range msgs {
+		dec.Reset(msg)
+		assert.Equal(t, 0, dec.Offset())
+		assert.Equal(t, len(msg), dec.Total())
+		for dec.More() {
+			tag, wt, err := dec.DecodeTag()
+			assert.NoError(t, err)
+			_, err = dec.Skip(tag, wt)
+			assert.NoError(t, err)
+			tags = append(tags, tag)
+		}
+	}
+	assert.Equal(t, []int{1, 1, 2}, tags)
+	assert.Equal(t, csproto.DecoderModeFast, dec.Mode(), "options should be retained")
+
+	// Reset() without a buffer rewinds the current one
+	dec.Reset()
+	assert.Equal(t, 0, dec.Offset())
+	assert.Equal(t, len(msgs[2]), dec.Total())
+}
+
+func TestDecoderPeek(t *testing.T) {
+	testData := []byte{0x08, 0x01, 0x10, 0x00, 0x1A, 0xE, 0x74, 0x68, 0x69, 0x73, 0x20, 0x69, 0x73, 0x20, 0x61, 0x20, 0x74, 0x65, 0x73, 0x74}
+	dec := csproto.NewDecoder(testData)
+
+	for dec.More() {
+		offset := dec.Offset()
+		tag, wt, err := dec.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, offset, dec.Offset(), "Peek() should not advance the offset")
+		tag2, wt2, err := dec.Peek()
+		assert.NoError(t, err)
+		assert.Equal(t, offset, dec.Offset(), "Peek() should not advance the offset")
+		assert.Equal(t, tag, tag2)
+		assert.Equal(t, wt, wt2)
+
+		gotTag, gotWireType, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		assert.Equal(t, tag, gotTag, "DecodeTag() should return the peeked tag")
+		assert.Equal(t, wt, gotWireType, "DecodeTag() should return the peeked wire type")
+
+		_, err = dec.Skip(gotTag, gotWireType)
+		assert.NoError(t, err)
+	}
+	_, _, err := dec.Peek()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestDecoderDecodeRawField(t *testing.T) {
+	var data = []byte{
+		// 1 (varint): 150
+		0x08, 0x96, 0x01,
+		// 2 (fixed64): 1
+		0x11, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// 3 (length-delimited): "test"
+		0x1A, 0x04, 't', 'e', 's', 't',
+		// 4 (fixed32): 1
+		0x25, 0x01, 0x00, 0x00, 0x00,
+		// 5 (group): { 1 (varint): 1 }
+		0x2B, 0x08, 0x01, 0x2C,
+	}
+	expected := []struct {
+		tag int
+		wt  csproto.WireType
+		raw []byte
+		// the offset of the raw value in data
+		offset int
+	}{
+		{1, csproto.WireTypeVarint, []byte{0x96, 0x01}, 1},
+		{2, csproto.WireTypeFixed64, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, 4},
+		{3, csproto.WireTypeLengthDelimited, []byte("test"), 14},
+		{4, csproto.WireTypeFixed32, []byte{0x01, 0x00, 0x00, 0x00}, 19},
+		{5, csproto.WireTypeStartGroup, []byte{0x08, 0x01, 0x2C}, 24},
+	}
+	for _, mode := range []csproto.DecoderMode{csproto.DecoderModeSafe, csproto.DecoderModeFast} {
+		mode := mode
+		t.Run(mode.String(), func(t *testing.T) {
+			dec := csproto.NewDecoder(data, csproto.WithMode(mode))
+			for _, want := range expected {
+				tag, wt, raw, err := dec.DecodeRawField()
+				assert.NoError(t, err)
+				assert.Equal(t, want.tag, tag)
+				assert.Equal(t, want.wt, wt)
+				assert.Equal(t, want.raw, raw)
+				// the raw value should only refer to the decoder's buffer in fast mode
+				assert.Equal(t, mode == csproto.DecoderModeFast, &raw[0] == &data[want.offset])
+			}
+			assert.False(t, dec.More())
+			_, _, _, err := dec.DecodeRawField()
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+	t.Run("truncated value", func(t *testing.T) {
+		dec := csproto.NewDecoder([]byte{0x1A, 0x04, 't', 'e'})
+		_, _, _, err := dec.DecodeRawField()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, 0, dec.Offset(), "the offset should not change on error")
+	})
+}
+
+func TestDecoderForEach(t *testing.T) {
+	var data = []byte{
+		// 1 (varint): 150
+		0x08, 0x96, 0x01,
+		// 2 (length-delimited): "test"
+		0x12, 0x04, 't', 'e', 's', 't',
+		// 3 (fixed32): 1
+		0x1D, 0x01, 0x00, 0x00, 0x00,
+	}
+	t.Run("visits all fields", func(t *testing.T) {
+		var (
+			tags []int
+			wts  []csproto.WireType
+			raws [][]byte
+		)
+		err := csproto.NewDecoder(data).ForEach(func(tag int, wt csproto.WireType, raw []byte) error {
+			tags = append(tags, tag)
+			wts = append(wts, wt)
+			raws = append(raws, raw)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, tags)
+		assert.Equal(t, []csproto.WireType{csproto.WireTypeVarint, csproto.WireTypeLengthDelimited, csproto.WireTypeFixed32}, wts)
+		assert.Equal(t, [][]byte{{0x96, 0x01}, []byte("test"), {0x01, 0x00, 0x00, 0x00}}, raws)
+	})
+	t.Run("stops on callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var tags []int
+		dec := csproto.NewDecoder(data)
+		err := dec.ForEach(func(tag int, _ csproto.WireType, _ []byte) error {
+			tags = append(tags, tag)
+			if tag == 2 {
+				return errStop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, []int{1, 2}, tags)
+		assert.True(t, dec.More())
+	})
+	t.Run("returns decode errors", func(t *testing.T) {
+		var called int
+		err := csproto.NewDecoder(data[:6]).ForEach(func(int, csproto.WireType, []byte) error {
+			called++
+			return nil
+		})
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, 1, called)
+	})
+}
+
+func TestDecoderSetOffset(t *testing.T) {
+	var data = []byte{
+		// 1 (length-delimited): "test"
+		0x0A, 0x04, 't', 'e', 's', 't',
+	}
+	dec := csproto.NewDecoder(data)
+	_, _, err := dec.DecodeTag()
+	assert.NoError(t, err)
+
+	// speculatively decode the value as a fixed64, which fails, then restore the offset and decode it as a string
+	checkpoint := dec.Offset()
+	_, err = dec.DecodeFixed64()
+	assert.Error(t, err)
+	assert.NoError(t, dec.SetOffset(checkpoint))
+	s, err := dec.DecodeString()
+	assert.NoError(t, err)
+	assert.Equal(t, "test", s)
+	assert.False(t, dec.More())
+
+	// move back to the start of the value and read it again as bytes
+	assert.NoError(t, dec.SetOffset(checkpoint))
+	b, err := dec.DecodeBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test"), b)
+
+	assert.NoError(t, dec.SetOffset(len(data)), "offset at the end of the data should be valid")
+	assert.False(t, dec.More())
+	assert.Error(t, dec.SetOffset(-1))
+	assert.Error(t, dec.SetOffset(len(data)+1))
+	assert.Equal(t, len(data), dec.Offset(), "failed SetOffset() should not change the offset")
+}
+
+func TestNewDecoderWithOptions(t *testing.T) {
+	t.Parallel()
+	var data = []byte{
+		// 1 (varint): 42
+		0x8, 0x2A,
+		// 2 (length-delimited): "test"
+		0x12, 0x04, 't', 'e', 's', 't',
+	}
+	t.Run("no options", func(t *testing.T) {
+		t.Parallel()
+		dec := csproto.NewDecoder(data)
+		assert.Equal(t, csproto.DecoderModeSafe, dec.Mode())
+	})
+	t.Run("with mode", func(t *testing.T) {
+		t.Parallel()
+		dec := csproto.NewDecoder(data, csproto.WithMode(csproto.DecoderModeFast))
+		assert.Equal(t, csproto.DecoderModeFast, dec.Mode())
+	})
+	t.Run("with max message size", func(t *testing.T) {
+		t.Parallel()
+		dec := csproto.NewDecoder(data, csproto.WithMaxMessageSize(len(data)))
+		_, _, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		v, err := dec.DecodeInt32()
+		assert.NoError(t, err)
+		assert.Equal(t, int32(42), v)
+
+		dec = csproto.NewDecoder(data, csproto.WithMaxMessageSize(len(data)-1))
+		_, _, err = dec.DecodeTag()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		_, err = dec.DecodeInt32()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		_, err = dec.DecodeString()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		_, err = dec.DecodeFloat64()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		_, err = dec.DecodePackedInt32()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		_, err = dec.Skip(1, csproto.WireTypeVarint)
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+	})
+	t.Run("with strict wire types", func(t *testing.T) {
+		t.Parallel()
+		var groupData = []byte{
+			// 1 (start group)
+			0x0B,
+			// 2 (varint): 1
+			0x10, 0x01,
+			// 1 (end group)
+			0x0C,
+		}
+		dec := csproto.NewDecoder(groupData, csproto.WithStrictWireTypes(false))
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, tag)
+		assert.Equal(t, csproto.WireTypeStartGroup, wt)
+
+		dec = csproto.NewDecoder(groupData, csproto.WithStrictWireTypes(true))
+		_, _, err = dec.DecodeTag()
+		assert.ErrorIs(t, err, csproto.ErrDeprecatedWireType)
+		assert.Equal(t, 0, dec.Offset(), "the offset should not change")
+
+		dec = csproto.NewDecoder(data, csproto.WithStrictWireTypes(true))
+		for dec.More() {
+			tag, wt, err := dec.DecodeTag()
+			assert.NoError(t, err)
+			_, err = dec.Skip(tag, wt)
+			assert.NoError(t, err)
+		}
+	})
+}
+
+func TestDecoderBufferAndSlice(t *testing.T) {
+	testData := []byte{0x08, 0x01, 0x10, 0x00, 0x1A, 0xE, 0x74, 0x68, 0x69, 0x73, 0x20, 0x69, 0x73, 0x20, 0x61, 0x20, 0x74, 0x65, 0x73, 0x74}
+	dec := csproto.NewDecoder(testData)
+
+	assert.Equal(t, testData, dec.Buffer())
+
+	t.Run("skipped field", func(t *testing.T) {
+		dec.Reset()
+		_, _, _ = dec.DecodeTag()
+		_, _ = dec.DecodeBool()
+		start := dec.Offset()
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		_, err = dec.Skip(tag, wt)
+		assert.NoError(t, err)
+
+		got, err := dec.Slice(start, dec.Offset())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x10, 0x00}, got)
+	})
+	t.Run("valid ranges", func(t *testing.T) {
+		got, err := dec.Slice(6, len(testData))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("this is a test"), got)
+
+		got, err = dec.Slice(3, 3)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+	t.Run("out of bounds", func(t *testing.T) {
+		for _, r := range [][2]int{{-1, 2}, {0, len(testData) + 1}, {4, 3}, {len(testData) + 1, len(testData) + 2}} {
+			_, err := dec.Slice(r[0], r[1])
+			assert.Error(t, err, "expected an error for range [%d:%d]", r[0], r[1])
+		}
+	})
+}
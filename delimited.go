@@ -0,0 +1,177 @@
+package csproto
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StreamDecoder reads a stream of length-delimited Protobuf messages, where each message is preceded by
+// its varint-encoded length, from an [io.Reader].  This is the same format used by the Java
+// writeDelimitedTo()/parseDelimitedFrom() APIs and by Google's protodelim package.
+//
+// For streams that use a fixed-size length prefix, use the stream package instead.
+type StreamDecoder struct {
+	r    byteReader
+	opts delimitedOptions
+}
+
+// DefaultMaxDelimitedSize is the maximum size of a single message read by a StreamDecoder or by
+// UnmarshalDelimited() unless a different limit is configured using WithMaxDelimitedSize().
+const DefaultMaxDelimitedSize = 4 << 20
+
+// DelimitedOption defines a function that sets an option for reading length-delimited messages
+type DelimitedOption func(*delimitedOptions)
+
+// delimitedOptions holds the options for reading length-delimited messages
+type delimitedOptions struct {
+	maxSize int
+}
+
+// WithMaxDelimitedSize returns a DelimitedOption that limits the size of the messages that can be read.
+// If the varint-encoded length of the next message is larger than n bytes, an error that wraps
+// ErrMessageTooLarge is returned without reading or allocating space for the message data.  A value of
+// zero or less disables the limit.
+//
+// The default limit is DefaultMaxDelimitedSize.
+func WithMaxDelimitedSize(n int) DelimitedOption {
+	return func(o *delimitedOptions) {
+		o.maxSize = n
+	}
+}
+
+// newDelimitedOptions returns the options for reading length-delimited messages with opts applied
+func newDelimitedOptions(opts []DelimitedOption) delimitedOptions {
+	o := delimitedOptions{maxSize: DefaultMaxDelimitedSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// byteReader is a reader that can also read a single byte at a time
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// NewStreamDecoder returns a new StreamDecoder that reads from r.  If r does not implement
+// [io.ByteReader], it is wrapped in a [bufio.Reader], so the decoder may read past the end of the last
+// message returned by Next().  The behavior of the decoder can be customized by passing one or more
+// DelimitedOption values.
+func NewStreamDecoder(r io.Reader, opts ...DelimitedOption) *StreamDecoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &StreamDecoder{r: br, opts: newDelimitedOptions(opts)}
+}
+
+// Next reads the next message from the stream and returns the binary Protobuf data, which can be passed
+// to NewDecoder() or Unmarshal().  A new buffer is allocated for each message.
+//
+// If there are no more messages in the stream, Next returns [io.EOF].  If the stream ends in the middle
+// of a message, the returned error wraps [io.ErrUnexpectedEOF].  If the message is larger than the limit
+// set by WithMaxDelimitedSize(), the returned error wraps ErrMessageTooLarge and the message data is left
+// unread, so the stream cannot be read any further.
+func (d *StreamDecoder) Next() ([]byte, error) {
+	return readDelimited(d.r, d.opts.maxSize)
+}
+
+// MarshalDelimited writes the binary Protobuf encoding of msg to w preceded by its varint-encoded length
+// and returns the number of bytes written.
+//
+// Like Marshal(), msg can be a message generated by any of the supported Protobuf runtimes.
+func MarshalDelimited(w io.Writer, msg interface{}) (int, error) {
+	data, err := Marshal(msg)
+	if err != nil {
+		return 0, fmt.Errorf("unable to marshal message: %w", err)
+	}
+	var prefix [10]byte
+	n, err := w.Write(prefix[:EncodeVarint(prefix[:], uint64(len(data)))])
+	if err != nil {
+		return n, fmt.Errorf("unable to write message length: %w", err)
+	}
+	nn, err := w.Write(data)
+	if err != nil {
+		return n + nn, fmt.Errorf("unable to write message data: %w", err)
+	}
+	return n + nn, nil
+}
+
+// UnmarshalDelimited reads a single length-delimited message from r, as written by MarshalDelimited(),
+// and unmarshals it into msg.  Only the bytes for that message are read from r, so the remaining data
+// can be read by subsequent calls.  Use a StreamDecoder to read many messages more efficiently.
+//
+// Like Unmarshal(), msg can be a message generated by any of the supported Protobuf runtimes.  If r is at
+// the end of its data, UnmarshalDelimited returns [io.EOF].  The size of the message is limited in the
+// same way as for a StreamDecoder and can be changed by passing WithMaxDelimitedSize().
+func UnmarshalDelimited(r io.Reader, msg interface{}, opts ...DelimitedOption) error {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = singleByteReader{r}
+	}
+	data, err := readDelimited(br, newDelimitedOptions(opts).maxSize)
+	if err != nil {
+		return err
+	}
+	if err = Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("unable to unmarshal message: %w", err)
+	}
+	return nil
+}
+
+// readDelimited reads the varint-encoded length of the next message from r, then the message data.  If
+// maxSize is greater than zero, messages larger than maxSize bytes are rejected before the data is read.
+func readDelimited(r byteReader, maxSize int) ([]byte, error) {
+	var l uint64
+	for shift := uint(0); ; shift += 7 {
+		if shift >= 64 {
+			return nil, fmt.Errorf("unable to read message length: %w", ErrValueOverflow)
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if shift == 0 {
+					return nil, io.EOF
+				}
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("unable to read message length: %w", err)
+		}
+		l |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			break
+		}
+	}
+	if l > maxFieldLen {
+		return nil, fmt.Errorf("invalid message length (%d): %w", l, ErrLenOverflow)
+	}
+	if maxSize > 0 && l > uint64(maxSize) {
+		return nil, fmt.Errorf("%w: %d bytes is larger than the limit of %d bytes", ErrMessageTooLarge, l, maxSize)
+	}
+	data := make([]byte, l)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("unable to read message data: %w", err)
+	}
+	return data, nil
+}
+
+// singleByteReader adapts an [io.Reader] to [io.ByteReader] without buffering so that no data past the
+// end of the current message is consumed.
+type singleByteReader struct {
+	io.Reader
+}
+
+// ReadByte reads and returns a single byte from the underlying reader.
+func (r singleByteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r.Reader, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
@@ -0,0 +1,161 @@
+package csproto_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+func TestMarshalUnmarshalDelimited(t *testing.T) {
+	t.Parallel()
+	msgs := []*wrapperspb.StringValue{
+		wrapperspb.String("one"),
+		wrapperspb.String(""),
+		wrapperspb.String("three"),
+	}
+
+	var buf bytes.Buffer
+	for _, m := range msgs {
+		n, err := csproto.MarshalDelimited(&buf, m)
+		require.NoError(t, err)
+		assert.Equal(t, csproto.SizeOfVarint(uint64(proto.Size(m)))+proto.Size(m), n)
+	}
+	data := buf.Bytes()
+
+	t.Run("unmarshal delimited", func(t *testing.T) {
+		t.Parallel()
+		// read one byte at a time to verify that no data past the end of each message is consumed
+		r := iotest.OneByteReader(bytes.NewReader(data))
+		for i, expected := range msgs {
+			var got wrapperspb.StringValue
+			require.NoError(t, csproto.UnmarshalDelimited(r, &got), "error decoding message %d", i)
+			assert.True(t, proto.Equal(expected, &got), "mismatched message %d: expected %v, got %v", i, expected, &got)
+		}
+		var extra wrapperspb.StringValue
+		assert.ErrorIs(t, csproto.UnmarshalDelimited(r, &extra), io.EOF)
+	})
+	t.Run("stream decoder", func(t *testing.T) {
+		t.Parallel()
+		dec := csproto.NewStreamDecoder(iotest.OneByteReader(bytes.NewReader(data)))
+		for i, expected := range msgs {
+			msgData, err := dec.Next()
+			require.NoError(t, err, "error reading message %d", i)
+			var got wrapperspb.StringValue
+			require.NoError(t, csproto.Unmarshal(msgData, &got))
+			assert.True(t, proto.Equal(expected, &got), "mismatched message %d: expected %v, got %v", i, expected, &got)
+		}
+		_, err := dec.Next()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("compatible with protodelim", func(t *testing.T) {
+		t.Parallel()
+		var expected bytes.Buffer
+		for _, m := range msgs {
+			_, err := protodelim.MarshalTo(&expected, m)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, expected.Bytes(), data)
+
+		r := bufio.NewReader(bytes.NewReader(data))
+		for i, m := range msgs {
+			var got wrapperspb.StringValue
+			require.NoError(t, protodelim.UnmarshalFrom(r, &got), "error decoding message %d", i)
+			assert.True(t, proto.Equal(m, &got), "mismatched message %d: expected %v, got %v", i, m, &got)
+		}
+	})
+}
+
+func TestStreamDecoderTruncatedData(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "truncated length",
+			data: []byte{0x80},
+		},
+		{
+			name: "truncated message",
+			data: []byte{0x05, 0x0A, 0x03, 'o'},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := csproto.NewStreamDecoder(bytes.NewReader(tc.data)).Next()
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+			var msg wrapperspb.StringValue
+			err = csproto.UnmarshalDelimited(bytes.NewReader(tc.data), &msg)
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+	t.Run("length overflow", func(t *testing.T) {
+		t.Parallel()
+		data := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}
+		_, err := csproto.NewStreamDecoder(bytes.NewReader(data)).Next()
+		assert.ErrorIs(t, err, csproto.ErrValueOverflow)
+	})
+}
+
+func TestStreamDecoderMessageTooLarge(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	_, err := csproto.MarshalDelimited(&buf, wrapperspb.String("testing"))
+	require.NoError(t, err)
+	data := buf.Bytes()
+
+	t.Run("default limit", func(t *testing.T) {
+		t.Parallel()
+		// a length of math.MaxInt32 with no message data
+		data := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07}
+		_, err := csproto.NewStreamDecoder(bytes.NewReader(data)).Next()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		assert.NotErrorIs(t, err, io.ErrUnexpectedEOF, "should fail before reading the message data")
+
+		var msg wrapperspb.StringValue
+		err = csproto.UnmarshalDelimited(bytes.NewReader(data), &msg)
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+	})
+	t.Run("configured limit", func(t *testing.T) {
+		t.Parallel()
+		opt := csproto.WithMaxDelimitedSize(len(data) - 2)
+		_, err := csproto.NewStreamDecoder(bytes.NewReader(data), opt).Next()
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+
+		var msg wrapperspb.StringValue
+		err = csproto.UnmarshalDelimited(bytes.NewReader(data), &msg, opt)
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+	})
+	t.Run("message at limit", func(t *testing.T) {
+		t.Parallel()
+		opt := csproto.WithMaxDelimitedSize(len(data) - 1)
+		got, err := csproto.NewStreamDecoder(bytes.NewReader(data), opt).Next()
+		assert.NoError(t, err)
+		assert.Equal(t, data[1:], got)
+
+		var msg wrapperspb.StringValue
+		err = csproto.UnmarshalDelimited(bytes.NewReader(data), &msg, opt)
+		assert.NoError(t, err)
+		assert.Equal(t, "testing", msg.GetValue())
+	})
+	t.Run("limit disabled", func(t *testing.T) {
+		t.Parallel()
+		// a length of 8 MiB, which is larger than the default limit, with no message data
+		data := []byte{0x80, 0x80, 0x80, 0x04}
+		_, err := csproto.NewStreamDecoder(bytes.NewReader(data), csproto.WithMaxDelimitedSize(0)).Next()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
@@ -2,14 +2,32 @@ package csproto
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"math"
 	"unsafe"
 )
 
+// ErrBufferTooSmall is returned by Encoder.Err() when a write operation failed because the remaining
+// space in the encoder's buffer was not large enough to hold the encoded data.
+var ErrBufferTooSmall = errors.New("buffer is too small to hold the encoded data")
+
+// ErrPatchOutOfBounds is returned when patching a value into the encoder's buffer at a position that is
+// outside of the data that has already been written.
+var ErrPatchOutOfBounds = errors.New("patch location is outside of the encoded data")
+
 // Encoder implements a binary Protobuf Encoder by sequentially writing to a wrapped []byte.
+//
+// If the buffer is too small to hold a value, nothing is written and all subsequent write operations
+// are ignored.  Callers that do not pre-size the buffer using Size() should check Err() after encoding.
 type Encoder struct {
 	p      []byte
 	offset int
+	// the largest offset that has been written to, which can be larger than offset after SetOffset()
+	written int
+	// the number of bytes that could not be written because the buffer was too small
+	overflow int
+	err      error
 }
 
 // NewEncoder initializes a new Protobuf encoder to write to the specified buffer, which must be
@@ -21,8 +39,150 @@ func NewEncoder(p []byte) *Encoder {
 	}
 }
 
+// Reset discards the encoder's state and switches to writing to p, which allows a single Encoder to be
+// pooled and reused for multiple messages rather than allocating a new one for each.
+func (e *Encoder) Reset(p []byte) {
+	e.p = p
+	e.offset = 0
+	e.written = 0
+	e.overflow = 0
+	e.err = nil
+}
+
+// Written returns the number of bytes that have been written to the buffer so far.  If SetOffset() has
+// been used to move the write offset backwards, the returned value still includes the data past the
+// current offset.
+//
+// After the buffer overflows, the returned value also includes the size of all of the values that could
+// not be written, so it is the total size that the buffer would need to hold all of the encoded data.
+func (e *Encoder) Written() int {
+	return e.writtenLen() + e.overflow
+}
+
+// Overflow returns true if any write operation failed because the buffer did not have enough space
+// remaining.  Once the buffer overflows, all subsequent write operations are ignored, so callers can
+// encode all fields then check Overflow() once at the end rather than checking the size of each field.
+func (e *Encoder) Overflow() bool {
+	return errors.Is(e.err, ErrBufferTooSmall)
+}
+
+// Err returns ErrBufferTooSmall if any write operation failed because the buffer did not have enough
+// space remaining, or nil otherwise.
+func (e *Encoder) Err() error {
+	return e.err
+}
+
+// Bookmark returns the current write offset, which can later be passed to SetOffset() to overwrite the
+// data written from that point.
+func (e *Encoder) Bookmark() int {
+	return e.offset
+}
+
+// SetOffset moves the write offset to the specified position so that subsequent write operations
+// overwrite previously written data, which supports patching values that can only be computed after
+// the rest of the message has been encoded.  The caller is responsible for ensuring that the data being
+// overwritten has the same size as the new data.
+//
+// An error is returned if offset is negative or is past the end of the data written so far.
+func (e *Encoder) SetOffset(offset int) error {
+	written := e.writtenLen()
+	if offset < 0 || offset > written {
+		return fmt.Errorf("offset (%d) out of bounds, must be between 0 and %d", offset, written)
+	}
+	e.written = written
+	e.offset = offset
+	return nil
+}
+
+// Mark returns the current write offset so that a value that can only be computed later, such as the
+// length of a nested message, can be written at that position using PatchUint32At() or PatchVarintAt().
+func (e *Encoder) Mark() int {
+	return e.offset
+}
+
+// PatchUint32At overwrites the 4 bytes at offset, which must be within the data that has already been
+// written, with the little-endian encoding of v.  The current write offset is not changed.
+//
+// If the range is outside of the written data, nothing is written and an error that wraps
+// ErrPatchOutOfBounds is returned.  As with PatchVarintAt(), a failed patch does not affect Err() or
+// subsequent write operations.
+func (e *Encoder) PatchUint32At(offset int, v uint32) error {
+	if err := e.checkPatchRange(offset, 4); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint32(e.p[offset:], v)
+	return nil
+}
+
+// PatchVarintAt overwrites the reserved bytes at offset, which must be within the data that has already
+// been written, with the varint encoding of v padded to exactly reserved bytes.  The current write
+// offset is not changed.
+//
+// This supports writing a placeholder of reserved bytes for a length prefix before encoding the content,
+// then patching in the actual length afterwards.  The padded encoding uses redundant continuation bytes,
+// which is valid Protobuf varint data.
+//
+// An error is returned if reserved is not between 1 and 10, if v cannot be encoded in reserved bytes, or
+// if the range is outside of the written data, in which case nothing is written.  A failed patch does not
+// affect Err() or subsequent write operations.
+func (e *Encoder) PatchVarintAt(offset int, v uint64, reserved int) error {
+	if reserved < 1 || reserved > binary.MaxVarintLen64 {
+		return fmt.Errorf("invalid reserved size (%d) for varint, must be between 1 and %d", reserved, binary.MaxVarintLen64)
+	}
+	if sz := SizeOfVarint(v); sz > reserved {
+		return fmt.Errorf("varint value %d requires %d bytes but only %d are reserved", v, sz, reserved)
+	}
+	if err := e.checkPatchRange(offset, reserved); err != nil {
+		return err
+	}
+	for i := 0; i < reserved-1; i++ {
+		e.p[offset+i] = byte(v) | 0x80
+		v >>= 7
+	}
+	e.p[offset+reserved-1] = byte(v)
+	return nil
+}
+
+// checkPatchRange returns an error if the n bytes starting at offset are not within the written data.
+func (e *Encoder) checkPatchRange(offset, n int) error {
+	if written := e.writtenLen(); offset < 0 || offset+n > written {
+		return fmt.Errorf("%w: range [%d:%d] is outside of the %d bytes written", ErrPatchOutOfBounds, offset, offset+n, written)
+	}
+	return nil
+}
+
+// writtenLen returns the number of bytes that have been written to the buffer, including any data
+// past the current offset after a call to SetOffset().
+func (e *Encoder) writtenLen() int {
+	if e.offset > e.written {
+		return e.offset
+	}
+	return e.written
+}
+
+// fits returns true if the buffer has at least n bytes remaining and no previous write operation has
+// failed.  If there is not enough space, the encoder's error is set to ErrBufferTooSmall and n is added
+// to the overflow count.
+func (e *Encoder) fits(n int) bool {
+	if e.err != nil {
+		if e.Overflow() {
+			e.overflow += n
+		}
+		return false
+	}
+	if len(e.p)-e.offset < n {
+		e.err = ErrBufferTooSmall
+		e.overflow += n
+		return false
+	}
+	return true
+}
+
 // EncodeBool writes a varint-encoded boolean value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeBool(tag int, v bool) {
+	if !e.fits(SizeOfTagKey(tag) + 1) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	if v {
 		e.p[e.offset] = 1
@@ -40,6 +200,9 @@ func (e *Encoder) EncodeString(tag int, s string) {
 
 // EncodeBytes writes a length-delimited byte slice to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeBytes(tag int, v []byte) {
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(v))) + len(v)) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(v)))
 	copy(e.p[e.offset:], v)
@@ -48,43 +211,70 @@ func (e *Encoder) EncodeBytes(tag int, v []byte) {
 
 // EncodeUInt32 writes a varint-encoded 32-bit unsigned integer value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeUInt32(tag int, v uint32) {
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(v))) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(v))
 }
 
 // EncodeUInt64 writes a varint-encoded 64-bit unsigned integer value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeUInt64(tag int, v uint64) {
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(v)) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	e.offset += EncodeVarint(e.p[e.offset:], v)
 }
 
 // EncodeInt32 writes a varint-encoded 32-bit signed integer value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeInt32(tag int, v int32) {
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(v))) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(v))
 }
 
 // EncodeInt64 writes a varint-encoded 64-bit signed integer value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeInt64(tag int, v int64) {
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(v))) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(v))
 }
 
 // EncodeSInt32 writes a zigzag-encoded 32-bit signed integer value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeSInt32(tag int, v int32) {
+	if !e.fits(SizeOfSInt32(tag, v)) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	e.offset += EncodeZigZag32(e.p[e.offset:], v)
 }
 
 // EncodeSInt64 writes a zigzag-encoded 64-bit signed integer value to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeSInt64(tag int, v int64) {
+	if !e.fits(SizeOfSInt64(tag, v)) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeVarint)
 	e.offset += EncodeZigZag64(e.p[e.offset:], v)
 }
 
+// EncodeEnum writes a varint-encoded enum value to the buffer preceded by the varint-encoded tag key.
+// Enum values are encoded the same as 32-bit signed integers.
+func (e *Encoder) EncodeEnum(tag int, v int32) {
+	e.EncodeInt32(tag, v)
+}
+
 // EncodeFixed32 writes a 32-bit unsigned integer value to the buffer using 4 bytes in little endian format,
 // preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeFixed32(tag int, v uint32) {
+	if !e.fits(SizeOfTagKey(tag) + 4) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeFixed32)
 	e.offset += EncodeFixed32(e.p[e.offset:], v)
 }
@@ -92,13 +282,31 @@ func (e *Encoder) EncodeFixed32(tag int, v uint32) {
 // EncodeFixed64 writes a 64-bit unsigned integer value to the buffer using 8 bytes in little endian format,
 // preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeFixed64(tag int, v uint64) {
+	if !e.fits(SizeOfTagKey(tag) + 8) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeFixed64)
 	e.offset += EncodeFixed64(e.p[e.offset:], v)
 }
 
+// EncodeSFixed32 writes a 32-bit signed integer value to the buffer using 4 bytes in little endian format,
+// preceded by the varint-encoded tag key.
+func (e *Encoder) EncodeSFixed32(tag int, v int32) {
+	e.EncodeFixed32(tag, uint32(v))
+}
+
+// EncodeSFixed64 writes a 64-bit signed integer value to the buffer using 8 bytes in little endian format,
+// preceded by the varint-encoded tag key.
+func (e *Encoder) EncodeSFixed64(tag int, v int64) {
+	e.EncodeFixed64(tag, uint64(v))
+}
+
 // EncodeFloat32 writes a 32-bit IEEE 754 floating point value to the buffer using 4 bytes in little endian format,
 // preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeFloat32(tag int, v float32) {
+	if !e.fits(SizeOfTagKey(tag) + 4) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeFixed32)
 	binary.LittleEndian.PutUint32(e.p[e.offset:], math.Float32bits(v))
 	e.offset += 4
@@ -107,6 +315,9 @@ func (e *Encoder) EncodeFloat32(tag int, v float32) {
 // EncodeFloat64 writes a 64-bit IEEE 754 floating point value to the buffer using 8 bytes in little endian format,
 // preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeFloat64(tag int, v float64) {
+	if !e.fits(SizeOfTagKey(tag) + 8) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeFixed64)
 	binary.LittleEndian.PutUint64(e.p[e.offset:], math.Float64bits(v))
 	e.offset += 8
@@ -118,6 +329,9 @@ func (e *Encoder) EncodePackedBool(tag int, vs []bool) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs))) + len(vs)) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)))
 	for _, v := range vs {
@@ -139,11 +353,14 @@ func (e *Encoder) EncodePackedInt32(tag int, vs []int32) {
 	if len(vs) == 0 {
 		return
 	}
-	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	sz := 0
 	for _, v := range vs {
 		sz += SizeOfVarint(uint64(v))
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	for _, v := range vs {
 		e.offset += EncodeVarint(e.p[e.offset:], uint64(v))
@@ -159,11 +376,14 @@ func (e *Encoder) EncodePackedInt64(tag int, vs []int64) {
 	if len(vs) == 0 {
 		return
 	}
-	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	sz := 0
 	for _, v := range vs {
 		sz += SizeOfVarint(uint64(v))
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	for _, v := range vs {
 		e.offset += EncodeVarint(e.p[e.offset:], uint64(v))
@@ -179,11 +399,14 @@ func (e *Encoder) EncodePackedUInt32(tag int, vs []uint32) {
 	if len(vs) == 0 {
 		return
 	}
-	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	sz := 0
 	for _, v := range vs {
 		sz += SizeOfVarint(uint64(v))
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	for _, v := range vs {
 		e.offset += EncodeVarint(e.p[e.offset:], uint64(v))
@@ -199,11 +422,14 @@ func (e *Encoder) EncodePackedUInt64(tag int, vs []uint64) {
 	if len(vs) == 0 {
 		return
 	}
-	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	sz := 0
 	for _, v := range vs {
 		sz += SizeOfVarint(v)
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	for _, v := range vs {
 		e.offset += EncodeVarint(e.p[e.offset:], v)
@@ -219,11 +445,14 @@ func (e *Encoder) EncodePackedSInt32(tag int, vs []int32) {
 	if len(vs) == 0 {
 		return
 	}
-	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	sz := 0
 	for _, v := range vs {
 		sz += SizeOfZigZag(uint64(v))
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	for _, v := range vs {
 		e.offset += EncodeZigZag32(e.p[e.offset:], v)
@@ -239,23 +468,35 @@ func (e *Encoder) EncodePackedSInt64(tag int, vs []int64) {
 	if len(vs) == 0 {
 		return
 	}
-	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	sz := 0
 	for _, v := range vs {
 		sz += SizeOfZigZag(uint64(v))
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	for _, v := range vs {
 		e.offset += EncodeZigZag64(e.p[e.offset:], v)
 	}
 }
 
+// EncodePackedEnum writes a list of enum values to the buffer using packed encoding, preceded by
+// the varint-encoded tag key.
+func (e *Encoder) EncodePackedEnum(tag int, vs []int32) {
+	e.EncodePackedInt32(tag, vs)
+}
+
 // EncodePackedFixed32 writes a list of 32-bit fixed-width unsigned integers to the buffer using packed
 // encoding, preceded by the varint-encoded tag key.
 func (e *Encoder) EncodePackedFixed32(tag int, vs []uint32) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs)*4)) + len(vs)*4) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)*4))
 	for _, v := range vs {
@@ -270,6 +511,9 @@ func (e *Encoder) EncodePackedFixed64(tag int, vs []uint64) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs)*8)) + len(vs)*8) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)*8))
 	for _, v := range vs {
@@ -284,6 +528,9 @@ func (e *Encoder) EncodePackedSFixed32(tag int, vs []int32) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs)*4)) + len(vs)*4) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)*4))
 	for _, v := range vs {
@@ -298,6 +545,9 @@ func (e *Encoder) EncodePackedSFixed64(tag int, vs []int64) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs)*8)) + len(vs)*8) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)*8))
 	for _, v := range vs {
@@ -312,6 +562,9 @@ func (e *Encoder) EncodePackedFloat32(tag int, vs []float32) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs)*4)) + len(vs)*4) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)*4))
 	for _, v := range vs {
@@ -326,6 +579,9 @@ func (e *Encoder) EncodePackedFloat64(tag int, vs []float64) {
 	if len(vs) == 0 {
 		return
 	}
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(len(vs)*8)) + len(vs)*8) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(len(vs)*8))
 	for _, v := range vs {
@@ -337,6 +593,9 @@ func (e *Encoder) EncodePackedFloat64(tag int, vs []float64) {
 // EncodeNested writes a nested message to the buffer preceded by the varint-encoded tag key.
 func (e *Encoder) EncodeNested(tag int, m interface{}) error {
 	sz := Size(m)
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(sz)) + sz) {
+		return e.err
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(sz))
 	switch tv := m.(type) {
@@ -367,15 +626,55 @@ func (e *Encoder) EncodeNested(tag int, m interface{}) error {
 
 // EncodeRaw writes the raw bytes of d into the buffer at the current offset
 func (e *Encoder) EncodeRaw(d []byte) {
-	if l := len(d); l > 0 {
+	if l := len(d); l > 0 && e.fits(l) {
 		copy(e.p[e.offset:], d)
 		e.offset += l
 	}
 }
 
+// EncodeRawField writes a field with the specified tag and wire type whose value has already been
+// encoded, such as one read from other Protobuf data, preceded by the varint-encoded tag key.
+//
+// For WireTypeLengthDelimited, rawValue is the content of the field and is preceded by its varint-encoded
+// length.  For all other wire types, rawValue must be the complete encoded value and is written as-is.
+func (e *Encoder) EncodeRawField(tag int, wt WireType, rawValue []byte) {
+	if !e.fits(SizeOfTagKey(tag) + rawFieldValueSize(wt, rawValue)) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, wt)
+	if wt == WireTypeLengthDelimited {
+		e.offset += EncodeVarint(e.p[e.offset:], uint64(len(rawValue)))
+	}
+	copy(e.p[e.offset:], rawValue)
+	e.offset += len(rawValue)
+}
+
+// rawFieldValueSize returns the number of bytes written by EncodeRawField() for rawValue, excluding the tag
+func rawFieldValueSize(wt WireType, rawValue []byte) int {
+	if wt == WireTypeLengthDelimited {
+		return SizeOfVarint(uint64(len(rawValue))) + len(rawValue)
+	}
+	return len(rawValue)
+}
+
+// EncodeGroup writes a deprecated proto2 group to the buffer, which consists of the start-group tag
+// key, the already-encoded group contents in data, and the end-group tag key.
+func (e *Encoder) EncodeGroup(tag int, data []byte) {
+	if !e.fits(SizeOfGroup(tag, len(data))) {
+		return
+	}
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeStartGroup)
+	copy(e.p[e.offset:], data)
+	e.offset += len(data)
+	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeEndGroup)
+}
+
 // EncodeMapEntryHeader writes a map entry header into the buffer, which consists of the specified
 // tag with a wire type of WireTypeLengthDelimited followed by the varint encoded entry size.
 func (e *Encoder) EncodeMapEntryHeader(tag int, size int) {
+	if !e.fits(SizeOfTagKey(tag) + SizeOfVarint(uint64(size))) {
+		return
+	}
 	e.offset += EncodeTag(e.p[e.offset:], tag, WireTypeLengthDelimited)
 	e.offset += EncodeVarint(e.p[e.offset:], uint64(size))
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/CrowdStrike/csproto"
 )
@@ -179,6 +180,77 @@ func TestEncodePackedInt32(t *testing.T) {
 	assert.Equal(t, expected, dest)
 }
 
+func TestEncodeEnum(t *testing.T) {
+	vs := []int32{0, 1, -1}
+	// tag/value for 1 + packed tag/length/values for 2
+	dest := make([]byte, 2+14)
+	enc := csproto.NewEncoder(dest)
+	enc.EncodeEnum(1, 2)
+	enc.EncodePackedEnum(2, vs)
+
+	expected := make([]byte, len(dest))
+	enc = csproto.NewEncoder(expected)
+	enc.EncodeInt32(1, 2)
+	enc.EncodePackedInt32(2, vs)
+	assert.Equal(t, expected, dest, "enums should be encoded as int32")
+
+	dec := csproto.NewDecoder(dest)
+	_, _, err := dec.DecodeTag()
+	assert.NoError(t, err)
+	v, err := dec.DecodeEnum()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), v)
+	_, _, err = dec.DecodeTag()
+	assert.NoError(t, err)
+	got, err := dec.DecodePackedEnum()
+	assert.NoError(t, err)
+	assert.Equal(t, vs, got)
+}
+
+func TestEncodeSFixed(t *testing.T) {
+	vs32 := []int32{0, -1, math.MinInt32, math.MaxInt32}
+	vs64 := []int64{0, -1, math.MinInt64, math.MaxInt64}
+	dest := make([]byte, (1+4)+(1+8)+(2+len(vs32)*4)+(2+len(vs64)*8))
+	enc := csproto.NewEncoder(dest)
+	enc.EncodeSFixed32(1, -42)
+	enc.EncodeSFixed64(2, -42)
+	enc.EncodePackedSFixed32(3, vs32)
+	enc.EncodePackedSFixed64(4, vs64)
+
+	var expected []byte
+	expected = protowire.AppendTag(expected, 1, protowire.Fixed32Type)
+	expected = protowire.AppendFixed32(expected, uint32(0xFFFFFFD6))
+	expected = protowire.AppendTag(expected, 2, protowire.Fixed64Type)
+	expected = protowire.AppendFixed64(expected, uint64(0xFFFFFFFFFFFFFFD6))
+	assert.Equal(t, expected, dest[:len(expected)])
+
+	dec := csproto.NewDecoder(dest)
+	_, _, err := dec.DecodeTag()
+	assert.NoError(t, err)
+	v32, err := dec.DecodeSFixed32()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(-42), v32)
+	_, _, err = dec.DecodeTag()
+	assert.NoError(t, err)
+	v64, err := dec.DecodeSFixed64()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-42), v64)
+	_, _, err = dec.DecodeTag()
+	assert.NoError(t, err)
+	got32, err := dec.DecodePackedSFixed32()
+	assert.NoError(t, err)
+	assert.Equal(t, vs32, got32)
+	_, _, err = dec.DecodeTag()
+	assert.NoError(t, err)
+	got64, err := dec.DecodePackedSFixed64()
+	assert.NoError(t, err)
+	assert.Equal(t, vs64, got64)
+	assert.False(t, dec.More())
+
+	_, err = csproto.NewDecoder([]byte{0x01, 0x02}).DecodeSFixed32()
+	assert.Error(t, err)
+}
+
 func TestEncodeInt64(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -802,6 +874,86 @@ func TestEncodeRaw(t *testing.T) {
 	assert.Equal(t, data, buf)
 }
 
+func TestEncodeRawField(t *testing.T) {
+	cases := []struct {
+		name     string
+		wireType csproto.WireType
+		rawValue []byte
+		encode   func(*csproto.Encoder)
+	}{
+		{
+			name:     "varint",
+			wireType: csproto.WireTypeVarint,
+			rawValue: []byte{0x96, 0x01},
+			encode:   func(enc *csproto.Encoder) { enc.EncodeUInt64(1, 150) },
+		},
+		{
+			name:     "fixed64",
+			wireType: csproto.WireTypeFixed64,
+			rawValue: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+			encode:   func(enc *csproto.Encoder) { enc.EncodeFixed64(1, 0x0102030405060708) },
+		},
+		{
+			name:     "length-delimited",
+			wireType: csproto.WireTypeLengthDelimited,
+			rawValue: []byte("this is a test"),
+			encode:   func(enc *csproto.Encoder) { enc.EncodeString(1, "this is a test") },
+		},
+		{
+			name:     "empty length-delimited",
+			wireType: csproto.WireTypeLengthDelimited,
+			rawValue: []byte{},
+			encode:   func(enc *csproto.Encoder) { enc.EncodeBytes(1, []byte{}) },
+		},
+		{
+			name:     "fixed32",
+			wireType: csproto.WireTypeFixed32,
+			rawValue: []byte{0x04, 0x03, 0x02, 0x01},
+			encode:   func(enc *csproto.Encoder) { enc.EncodeFixed32(1, 0x01020304) },
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := make([]byte, 32)
+			enc := csproto.NewEncoder(expected)
+			tc.encode(enc)
+
+			got := make([]byte, 32)
+			enc = csproto.NewEncoder(got)
+			enc.EncodeRawField(1, tc.wireType, tc.rawValue)
+
+			assert.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestEncodeRawFieldPassThrough(t *testing.T) {
+	src := []byte{
+		// 1 (varint): 150
+		0x08, 0x96, 0x01,
+		// 2 (length-delimited): "test"
+		0x12, 0x04, 't', 'e', 's', 't',
+		// 3 (fixed32): 1
+		0x1D, 0x01, 0x00, 0x00, 0x00,
+		// 4 (group): { 1 (varint): 1 }
+		0x23, 0x08, 0x01, 0x24,
+	}
+	// copy all fields except 3 verbatim
+	dest := make([]byte, len(src))
+	enc := csproto.NewEncoder(dest)
+	err := csproto.NewDecoder(src).ForEach(func(tag int, wt csproto.WireType, raw []byte) error {
+		if tag != 3 {
+			enc.EncodeRawField(tag, wt, raw)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, enc.Err())
+
+	expected := append(append([]byte{}, src[:9]...), src[14:]...)
+	assert.Equal(t, expected, dest[:enc.Written()])
+}
+
 type testNestedMsg struct {
 	Name  *string
 	Value *int32
@@ -835,3 +987,329 @@ func (m *testNestedMsg) MarshalTo(dest []byte) error {
 	}
 	return nil
 }
+
+func TestEncodeGroup(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		content := []byte{0x8, 0x2A, 0x12, 0x2, 0x68, 0x69}
+		buf := make([]byte, csproto.SizeOfGroup(2, len(content)))
+		enc := csproto.NewEncoder(buf)
+		enc.EncodeGroup(2, content)
+		assert.NoError(t, enc.Err())
+		assert.Equal(t, byte(0x13), buf[0], "should start with a start-group tag")
+		assert.Equal(t, byte(0x14), buf[len(buf)-1], "should end with an end-group tag")
+
+		dec := csproto.NewDecoder(buf)
+		tag, wt, err := dec.DecodeTag()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tag)
+		assert.Equal(t, csproto.WireTypeStartGroup, wt)
+		got, err := dec.DecodeGroup(tag)
+		assert.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.False(t, dec.More())
+	})
+	t.Run("nested group", func(t *testing.T) {
+		// 1 (varint): 1, 300 (group) { 1 (fixed32): 42 }, 2 (string): "inner"
+		inner := make([]byte, csproto.SizeOfTagKey(1)+4)
+		csproto.NewEncoder(inner).EncodeFixed32(1, 42)
+		contentSize := csproto.SizeOfTagKey(1) + 1 + csproto.SizeOfGroup(300, len(inner)) + csproto.SizeOfTagKey(2) + 1 + len("inner")
+		content := make([]byte, contentSize)
+		enc := csproto.NewEncoder(content)
+		enc.EncodeInt32(1, 1)
+		enc.EncodeGroup(300, inner)
+		enc.EncodeString(2, "inner")
+		assert.NoError(t, enc.Err())
+
+		buf := make([]byte, csproto.SizeOfGroup(7, len(content)))
+		enc = csproto.NewEncoder(buf)
+		enc.EncodeGroup(7, content)
+		assert.NoError(t, enc.Err())
+
+		// build the same data using the reference implementation
+		var expectedInner []byte
+		expectedInner = protowire.AppendTag(expectedInner, 1, protowire.Fixed32Type)
+		expectedInner = protowire.AppendFixed32(expectedInner, 42)
+		var expected []byte
+		expected = protowire.AppendTag(expected, 7, protowire.StartGroupType)
+		expected = protowire.AppendTag(expected, 1, protowire.VarintType)
+		expected = protowire.AppendVarint(expected, 1)
+		expected = protowire.AppendTag(expected, 300, protowire.StartGroupType)
+		expected = append(expected, expectedInner...)
+		expected = protowire.AppendTag(expected, 300, protowire.EndGroupType)
+		expected = protowire.AppendTag(expected, 2, protowire.BytesType)
+		expected = protowire.AppendString(expected, "inner")
+		expected = protowire.AppendTag(expected, 7, protowire.EndGroupType)
+
+		assert.Equal(t, expected, buf)
+		_, _, n := protowire.ConsumeField(buf)
+		assert.Equal(t, len(buf), n, "the reference implementation should consume the entire group")
+	})
+	t.Run("buffer too small", func(t *testing.T) {
+		buf := make([]byte, 3)
+		enc := csproto.NewEncoder(buf)
+		enc.EncodeGroup(1, []byte{0x8, 0x1})
+		assert.ErrorIs(t, enc.Err(), csproto.ErrBufferTooSmall)
+		assert.Equal(t, []byte{0, 0, 0}, buf)
+	})
+}
+
+func TestEncoderSetOffset(t *testing.T) {
+	const (
+		lengthTag = 1
+		nameTag   = 2
+		valueTag  = 3
+	)
+	name := "testing"
+	size := csproto.SizeOfTagKey(lengthTag) + 4 +
+		csproto.SizeOfTagKey(nameTag) + csproto.SizeOfVarint(uint64(len(name))) + len(name) +
+		csproto.SizeOfTagKey(valueTag) + csproto.SizeOfVarint(150)
+	buf := make([]byte, size)
+	enc := csproto.NewEncoder(buf)
+
+	// write a placeholder for the length of the remaining fields
+	placeholder := enc.Bookmark()
+	assert.Equal(t, 0, placeholder)
+	enc.EncodeFixed32(lengthTag, 0)
+	start := enc.Bookmark()
+	enc.EncodeString(nameTag, name)
+	enc.EncodeUInt32(valueTag, 150)
+	end := enc.Bookmark()
+	assert.Equal(t, size, end)
+
+	// patch the placeholder with the computed length then move back to the end
+	assert.NoError(t, enc.SetOffset(placeholder))
+	enc.EncodeFixed32(lengthTag, uint32(end-start))
+	assert.NoError(t, enc.SetOffset(end))
+	assert.NoError(t, enc.Err())
+
+	dec := csproto.NewDecoder(buf)
+	tag, _, _ := dec.DecodeTag()
+	assert.Equal(t, lengthTag, tag)
+	l, err := dec.DecodeFixed32()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(end-start), l)
+	assert.Equal(t, int(l), dec.Remaining())
+	tag, _, _ = dec.DecodeTag()
+	assert.Equal(t, nameTag, tag)
+	s, _ := dec.DecodeString()
+	assert.Equal(t, name, s)
+	tag, _, _ = dec.DecodeTag()
+	assert.Equal(t, valueTag, tag)
+	v, _ := dec.DecodeUInt32()
+	assert.Equal(t, uint32(150), v)
+
+	t.Run("invalid offsets", func(t *testing.T) {
+		buf := make([]byte, 10)
+		enc := csproto.NewEncoder(buf)
+		enc.EncodeBool(1, true)
+		assert.Error(t, enc.SetOffset(-1))
+		assert.Error(t, enc.SetOffset(3), "should not be able to move past the written data")
+		assert.Equal(t, 2, enc.Bookmark(), "the offset should not change on error")
+		// moving back does not reduce the amount of data that has been written
+		assert.NoError(t, enc.SetOffset(0))
+		assert.NoError(t, enc.SetOffset(2))
+	})
+}
+
+func TestEncoderResetAndWritten(t *testing.T) {
+	enc := csproto.NewEncoder(make([]byte, 2))
+	assert.Equal(t, 0, enc.Written())
+	enc.EncodeInt32(1, 1)
+	assert.Equal(t, 2, enc.Written())
+	// no space left, so the encoder fails
+	enc.EncodeInt32(2, 1)
+	assert.ErrorIs(t, enc.Err(), csproto.ErrBufferTooSmall)
+
+	// reuse the encoder with a new buffer
+	buf := make([]byte, 4)
+	enc.Reset(buf)
+	assert.NoError(t, enc.Err(), "Reset() should clear the error")
+	assert.Equal(t, 0, enc.Written())
+	enc.EncodeInt32(1, 1)
+	enc.EncodeInt32(2, 1)
+	assert.NoError(t, enc.Err())
+	assert.Equal(t, 4, enc.Written())
+	assert.Equal(t, []byte{0x08, 0x01, 0x10, 0x01}, buf)
+
+	// moving the offset back does not change the number of bytes written
+	assert.NoError(t, enc.SetOffset(1))
+	assert.Equal(t, 4, enc.Written())
+}
+
+func TestEncoderOverflow(t *testing.T) {
+	// the full message requires 2 + 6 + 2 = 10 bytes
+	enc := csproto.NewEncoder(make([]byte, 4))
+	enc.EncodeInt32(1, 1)
+	assert.False(t, enc.Overflow())
+	enc.EncodeString(2, "test")
+	assert.True(t, enc.Overflow())
+	assert.ErrorIs(t, enc.Err(), csproto.ErrBufferTooSmall)
+	// subsequent writes are no-ops, even if the value would fit
+	enc.EncodeBool(3, true)
+	assert.True(t, enc.Overflow())
+	assert.Equal(t, 2+6+2, enc.Written(), "Written() should include the size of the values that did not fit")
+
+	buf := make([]byte, enc.Written())
+	enc.Reset(buf)
+	assert.False(t, enc.Overflow())
+	enc.EncodeInt32(1, 1)
+	enc.EncodeString(2, "test")
+	enc.EncodeBool(3, true)
+	assert.False(t, enc.Overflow())
+	assert.NoError(t, enc.Err())
+	assert.Equal(t, len(buf), enc.Written())
+}
+
+func TestEncoderPatchNestedMessageLength(t *testing.T) {
+	const reserved = 2
+	buf := make([]byte, 64)
+	enc := csproto.NewEncoder(buf)
+	enc.EncodeInt32(1, 42)
+
+	// write the tag for the nested message at field 2 plus a placeholder for the length
+	tagKey := make([]byte, csproto.SizeOfTagKey(2))
+	csproto.EncodeTag(tagKey, 2, csproto.WireTypeLengthDelimited)
+	enc.EncodeRaw(tagKey)
+	lengthAt := enc.Mark()
+	enc.EncodeRaw(make([]byte, reserved))
+
+	// encode the nested message then patch in its length
+	start := enc.Mark()
+	enc.EncodeString(1, "nested")
+	enc.EncodeFixed32(2, 1138)
+	nestedLen := enc.Mark() - start
+	assert.NoError(t, enc.PatchVarintAt(lengthAt, uint64(nestedLen), reserved))
+
+	// follow the nested message with a field whose value is patched in afterwards
+	enc.EncodeFixed32(3, 0)
+	assert.NoError(t, enc.PatchUint32At(enc.Mark()-4, uint32(nestedLen)))
+	assert.NoError(t, enc.Err())
+	data := buf[:enc.Mark()]
+
+	// verify the result using the reference implementation
+	num, typ, n := protowire.ConsumeTag(data)
+	assert.Equal(t, protowire.Number(1), num)
+	assert.Equal(t, protowire.VarintType, typ)
+	data = data[n:]
+	v, n := protowire.ConsumeVarint(data)
+	assert.Equal(t, uint64(42), v)
+	data = data[n:]
+	num, typ, n = protowire.ConsumeTag(data)
+	assert.Equal(t, protowire.Number(2), num)
+	assert.Equal(t, protowire.BytesType, typ)
+	data = data[n:]
+	nested, n := protowire.ConsumeBytes(data)
+	assert.Equal(t, nestedLen+reserved, n)
+	data = data[n:]
+	num, _, n = protowire.ConsumeTag(data)
+	assert.Equal(t, protowire.Number(3), num)
+	data = data[n:]
+	f32, n := protowire.ConsumeFixed32(data)
+	assert.Equal(t, uint32(nestedLen), f32)
+	assert.Len(t, data, n, "should have consumed all of the data")
+
+	dec := csproto.NewDecoder(nested)
+	tag, _, _ := dec.DecodeTag()
+	assert.Equal(t, 1, tag)
+	s, _ := dec.DecodeString()
+	assert.Equal(t, "nested", s)
+	tag, _, _ = dec.DecodeTag()
+	assert.Equal(t, 2, tag)
+	f, _ := dec.DecodeFixed32()
+	assert.Equal(t, uint32(1138), f)
+	assert.False(t, dec.More())
+
+	t.Run("invalid patches", func(t *testing.T) {
+		buf := make([]byte, 10)
+		enc := csproto.NewEncoder(buf)
+		enc.EncodeRaw([]byte{0, 0, 0})
+		assert.Error(t, enc.PatchVarintAt(0, 300, 1), "value should not fit in 1 byte")
+		assert.Error(t, enc.PatchVarintAt(0, 1, 0), "reserved size must be positive")
+		assert.Error(t, enc.PatchVarintAt(0, 1, 11), "reserved size must be at most 10 bytes")
+		assert.ErrorIs(t, enc.PatchVarintAt(2, 1, 2), csproto.ErrPatchOutOfBounds)
+		assert.ErrorIs(t, enc.PatchVarintAt(-1, 1, 1), csproto.ErrPatchOutOfBounds)
+		assert.NoError(t, enc.Err(), "PatchVarintAt() should not affect Err()")
+		assert.Equal(t, []byte{0, 0, 0}, buf[:3], "failed patches should not write anything")
+
+		assert.ErrorIs(t, enc.PatchUint32At(0, 1), csproto.ErrPatchOutOfBounds)
+		assert.ErrorIs(t, enc.PatchUint32At(-1, 1), csproto.ErrPatchOutOfBounds)
+		assert.NoError(t, enc.Err(), "PatchUint32At() should not affect Err()")
+		assert.Equal(t, []byte{0, 0, 0}, buf[:3], "failed patches should not write anything")
+	})
+	t.Run("encoding after a failed patch", func(t *testing.T) {
+		buf := make([]byte, 10)
+		enc := csproto.NewEncoder(buf)
+		enc.EncodeRaw([]byte{0, 0, 0})
+		assert.Error(t, enc.PatchUint32At(0, 1))
+		assert.Error(t, enc.PatchVarintAt(2, 1, 2))
+
+		enc.EncodeBool(1, true)
+		assert.NoError(t, enc.Err())
+		assert.False(t, enc.Overflow())
+		assert.Equal(t, []byte{0, 0, 0, 0x08, 0x01}, buf[:enc.Written()])
+	})
+}
+
+func TestEncoderBufferTooSmall(t *testing.T) {
+	cases := []struct {
+		name   string
+		encode func(*csproto.Encoder)
+	}{
+		{name: "bool", encode: func(e *csproto.Encoder) { e.EncodeBool(1, true) }},
+		{name: "string", encode: func(e *csproto.Encoder) { e.EncodeString(1, "testing") }},
+		{name: "bytes", encode: func(e *csproto.Encoder) { e.EncodeBytes(1, []byte("testing")) }},
+		{name: "uint32", encode: func(e *csproto.Encoder) { e.EncodeUInt32(1, math.MaxUint32) }},
+		{name: "uint64", encode: func(e *csproto.Encoder) { e.EncodeUInt64(1, math.MaxUint64) }},
+		{name: "int32", encode: func(e *csproto.Encoder) { e.EncodeInt32(1, -1) }},
+		{name: "int64", encode: func(e *csproto.Encoder) { e.EncodeInt64(1, -1) }},
+		{name: "sint32", encode: func(e *csproto.Encoder) { e.EncodeSInt32(1, math.MinInt32) }},
+		{name: "sint64", encode: func(e *csproto.Encoder) { e.EncodeSInt64(1, math.MinInt64) }},
+		{name: "fixed32", encode: func(e *csproto.Encoder) { e.EncodeFixed32(1, 42) }},
+		{name: "fixed64", encode: func(e *csproto.Encoder) { e.EncodeFixed64(1, 42) }},
+		{name: "float32", encode: func(e *csproto.Encoder) { e.EncodeFloat32(1, 3.14) }},
+		{name: "float64", encode: func(e *csproto.Encoder) { e.EncodeFloat64(1, 3.14) }},
+		{name: "packed bool", encode: func(e *csproto.Encoder) { e.EncodePackedBool(1, []bool{true, false}) }},
+		{name: "packed int32", encode: func(e *csproto.Encoder) { e.EncodePackedInt32(1, []int32{1, -1}) }},
+		{name: "packed int64", encode: func(e *csproto.Encoder) { e.EncodePackedInt64(1, []int64{1, -1}) }},
+		{name: "packed uint32", encode: func(e *csproto.Encoder) { e.EncodePackedUInt32(1, []uint32{1, 1138}) }},
+		{name: "packed uint64", encode: func(e *csproto.Encoder) { e.EncodePackedUInt64(1, []uint64{1, 1138}) }},
+		{name: "packed sint32", encode: func(e *csproto.Encoder) { e.EncodePackedSInt32(1, []int32{1, -1138}) }},
+		{name: "packed sint64", encode: func(e *csproto.Encoder) { e.EncodePackedSInt64(1, []int64{1, -1138}) }},
+		{name: "packed fixed32", encode: func(e *csproto.Encoder) { e.EncodePackedFixed32(1, []uint32{1, 2}) }},
+		{name: "packed fixed64", encode: func(e *csproto.Encoder) { e.EncodePackedFixed64(1, []uint64{1, 2}) }},
+		{name: "packed sfixed32", encode: func(e *csproto.Encoder) { e.EncodePackedSFixed32(1, []int32{1, -2}) }},
+		{name: "packed sfixed64", encode: func(e *csproto.Encoder) { e.EncodePackedSFixed64(1, []int64{1, -2}) }},
+		{name: "packed float32", encode: func(e *csproto.Encoder) { e.EncodePackedFloat32(1, []float32{1, 2}) }},
+		{name: "packed float64", encode: func(e *csproto.Encoder) { e.EncodePackedFloat64(1, []float64{1, 2}) }},
+		{name: "raw", encode: func(e *csproto.Encoder) { e.EncodeRaw([]byte{0x08, 0x01}) }},
+		{name: "raw field", encode: func(e *csproto.Encoder) { e.EncodeRawField(1, csproto.WireTypeLengthDelimited, []byte("testing")) }},
+		{name: "map entry header", encode: func(e *csproto.Encoder) { e.EncodeMapEntryHeader(1, 1138) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			enc := csproto.NewEncoder(make([]byte, 100))
+			tc.encode(enc)
+			assert.NoError(t, enc.Err())
+
+			sized := csproto.NewEncoder(make([]byte, 1))
+			tc.encode(sized)
+			assert.ErrorIs(t, sized.Err(), csproto.ErrBufferTooSmall)
+
+			// once a write has failed, subsequent writes are ignored even if they would fit
+			sized.EncodeRaw([]byte{0x01})
+			assert.ErrorIs(t, sized.Err(), csproto.ErrBufferTooSmall)
+		})
+	}
+}
+
+func TestEncoderBufferTooSmallDoesNotWrite(t *testing.T) {
+	dest := make([]byte, 4)
+	enc := csproto.NewEncoder(dest)
+	enc.EncodeBool(1, true)
+	enc.EncodeString(2, "testing")
+	enc.EncodeBool(3, true)
+
+	assert.ErrorIs(t, enc.Err(), csproto.ErrBufferTooSmall)
+	assert.Equal(t, []byte{0x08, 0x01, 0x00, 0x00}, dest, "only the first field should be written")
+}
@@ -0,0 +1,53 @@
+package example_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/example/proto3/gogo"
+	"github.com/CrowdStrike/csproto/example/proto3/googlev1"
+	"github.com/CrowdStrike/csproto/example/proto3/googlev2"
+)
+
+func TestCanMarshal(t *testing.T) {
+	t.Parallel()
+	type notAMessage struct {
+		Name string
+	}
+	cases := []struct {
+		name     string
+		msg      interface{}
+		expected bool
+	}{
+		{name: "nil", msg: nil, expected: false},
+		{name: "typed nil", msg: (*googlev2.TestEvent)(nil), expected: false},
+		{name: "non-proto struct", msg: &notAMessage{Name: "test"}, expected: false},
+		{name: "non-proto value", msg: 42, expected: false},
+		{name: "gogo message", msg: &gogo.TestEvent{Name: "test"}, expected: true},
+		{name: "google v1 message", msg: &googlev1.TestEvent{Name: "test"}, expected: true},
+		{name: "google v2 message", msg: &googlev2.TestEvent{Name: "test"}, expected: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, csproto.CanMarshal(tc.msg))
+
+			pm, ok := csproto.AsProtoMessage(tc.msg)
+			assert.Equal(t, tc.expected, ok)
+			if !tc.expected {
+				assert.Nil(t, pm)
+				return
+			}
+			// the converted message should produce the same encoding as the original
+			expected, err := csproto.Marshal(tc.msg)
+			assert.NoError(t, err)
+			got, err := proto.Marshal(pm)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, got)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package example_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/example/proto3/gogo"
+	"github.com/CrowdStrike/csproto/example/proto3/googlev1"
+	"github.com/CrowdStrike/csproto/example/proto3/googlev2"
+)
+
+func TestMessageName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name          string
+		msg           interface{}
+		expectedName  protoreflect.FullName
+		expectedShort protoreflect.Name
+	}{
+		{
+			name:          "gogo message",
+			msg:           &gogo.TestEvent{},
+			expectedName:  "crowdstrike.csproto.example.proto3.gogo.TestEvent",
+			expectedShort: "TestEvent",
+		},
+		{
+			name:          "google v1 message",
+			msg:           &googlev1.TestEvent{},
+			expectedName:  "crowdstrike.csproto.example.proto3.googlev1.TestEvent",
+			expectedShort: "TestEvent",
+		},
+		{
+			name:          "google v2 message",
+			msg:           &googlev2.TestEvent{},
+			expectedName:  "crowdstrike.csproto.example.proto3.googlev2.TestEvent",
+			expectedShort: "TestEvent",
+		},
+		{
+			name:          "google v2 nested message",
+			msg:           &googlev2.TestEvent_NestedMsg{},
+			expectedName:  "crowdstrike.csproto.example.proto3.googlev2.TestEvent.NestedMsg",
+			expectedShort: "NestedMsg",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			name, err := csproto.MessageName(tc.msg)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedName, name)
+
+			short, err := csproto.MessageShortName(tc.msg)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedShort, short)
+		})
+	}
+	t.Run("invalid messages", func(t *testing.T) {
+		t.Parallel()
+		type notAMessage struct {
+			Name string
+		}
+		for _, msg := range []interface{}{nil, (*googlev2.TestEvent)(nil), &notAMessage{}, 42} {
+			_, err := csproto.MessageName(msg)
+			assert.Error(t, err, "expected an error for %T", msg)
+			_, err = csproto.MessageShortName(msg)
+			assert.Error(t, err, "expected an error for %T", msg)
+		}
+	})
+}
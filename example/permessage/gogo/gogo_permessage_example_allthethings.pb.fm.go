@@ -204,7 +204,7 @@ func (m *AllTheThings) MarshalTo(dest []byte) error {
 			return fmt.Errorf("unable to encode message data for field 'theMessage' (tag=18): %w", err)
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -107,7 +107,7 @@ func (m *EmbeddedEvent) MarshalTo(dest []byte) error {
 	if m.Size_ != 0 {
 		enc.EncodeInt32(5, m.Size_)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -87,7 +87,7 @@ func (m *I18NVariable) MarshalTo(dest []byte) error {
 			_ = typedVal // ensure no unused variable
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -158,7 +158,7 @@ func (m *TestEvent) MarshalTo(dest []byte) error {
 			_ = typedVal // ensure no unused variable
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
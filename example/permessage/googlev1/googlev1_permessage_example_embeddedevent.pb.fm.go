@@ -99,7 +99,7 @@ func (m *EmbeddedEvent) MarshalTo(dest []byte) error {
 	for _, val := range m.RandomThings {
 		enc.EncodeBytes(4, val)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -69,7 +69,7 @@ func (m *TestEvent_NestedMsg) MarshalTo(dest []byte) error {
 	if len(m.Details) > 0 {
 		enc.EncodeString(1, m.Details)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -134,9 +134,8 @@ func (m *RepeatAllTheThings) Size() int {
 	}
 	// TheMessages (message,repeated)
 	for _, val := range m.TheMessages {
-		if l = csproto.Size(val); l > 0 {
-			sz += csproto.SizeOfTagKey(18) + csproto.SizeOfVarint(uint64(l)) + l
-		}
+		l = csproto.Size(val)
+		sz += csproto.SizeOfTagKey(18) + csproto.SizeOfVarint(uint64(l)) + l
 	}
 	// cache the size so it can be re-used in Marshal()/MarshalTo()
 	atomic.StoreInt32(&m.sizeCache, int32(sz))
@@ -250,7 +249,7 @@ func (m *RepeatAllTheThings) MarshalTo(dest []byte) error {
 			return fmt.Errorf("unable to encode message data for field 'theMessages' (tag=18): %w", err)
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -392,7 +392,7 @@ func (m *AllTheMaps) MarshalTo(dest []byte) error {
 		enc.EncodeInt32(2, int32(v))
 	}
 
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
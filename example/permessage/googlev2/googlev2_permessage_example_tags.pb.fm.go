@@ -70,7 +70,7 @@ func (m *Msg_Tags) MarshalTo(dest []byte) error {
 	for _, val := range m.Tags {
 		enc.EncodeString(1, val)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
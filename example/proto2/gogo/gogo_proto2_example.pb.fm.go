@@ -5,7 +5,6 @@ package gogo
 
 import (
 	"fmt"
-	"strings"
 	"sync/atomic"
 	"github.com/CrowdStrike/csproto"
 	types "github.com/gogo/protobuf/types"
@@ -133,7 +132,7 @@ func (m *BaseEvent) MarshalTo(dest []byte) error {
 		}
 	}
 
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -253,15 +252,7 @@ func (m *BaseEvent) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -406,7 +397,7 @@ func (m *TestEvent) MarshalTo(dest []byte) error {
 			_ = typedVal // ensure no unused variable
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -540,15 +531,7 @@ func (m *TestEvent) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -640,7 +623,7 @@ func (m *EmbeddedEvent) MarshalTo(dest []byte) error {
 	for _, val := range m.RandomThings {
 		enc.EncodeBytes(4, val)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -729,15 +712,7 @@ func (m *EmbeddedEvent) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -940,7 +915,7 @@ func (m *AllTheThings) MarshalTo(dest []byte) error {
 			return fmt.Errorf("unable to encode message data for field 'theMessage' (tag=18): %w", err)
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1153,15 +1128,7 @@ func (m *AllTheThings) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -1256,9 +1223,8 @@ func (m *RepeatAllTheThings) Size() int {
 	}
 	// TheMessages (message,repeated)
 	for _, val := range m.TheMessages {
-		if l = csproto.Size(val); l > 0 {
-			sz += csproto.SizeOfTagKey(18) + csproto.SizeOfVarint(uint64(l)) + l
-		}
+		l = csproto.Size(val)
+		sz += csproto.SizeOfTagKey(18) + csproto.SizeOfVarint(uint64(l)) + l
 	}
 	// cache the size so it can be re-used in Marshal()/MarshalTo()
 	atomic.StoreInt32(&m.XXX_sizecache, int32(sz))
@@ -1369,7 +1335,7 @@ func (m *RepeatAllTheThings) MarshalTo(dest []byte) error {
 			return fmt.Errorf("unable to encode message data for field 'theMessages' (tag=18): %w", err)
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1677,15 +1643,7 @@ func (m *RepeatAllTheThings) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -1773,7 +1731,7 @@ func (m *AllOptionalFields) MarshalTo(dest []byte) error {
 		}
 	}
 
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1881,7 +1839,7 @@ func (m *EmptyExtension) MarshalTo(dest []byte) error {
 	_ = err
 	_ = extVal
 
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1989,7 +1947,7 @@ func (m *EventUsingWKTs) MarshalTo(dest []byte) error {
 	if m.EventType != nil {
 		enc.EncodeInt32(3, int32(*m.EventType))
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -2061,15 +2019,7 @@ func (m *EventUsingWKTs) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -2152,7 +2102,7 @@ func (m *I18NVariable) MarshalTo(dest []byte) error {
 			_ = typedVal // ensure no unused variable
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -2275,7 +2225,7 @@ func (m *Msg) MarshalTo(dest []byte) error {
 			_ = typedVal // ensure no unused variable
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -2376,7 +2326,7 @@ func (m *TestEvent_NestedMsg) MarshalTo(dest []byte) error {
 	if m.Details != nil {
 		enc.EncodeString(1, *m.Details)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -2474,7 +2424,7 @@ func (m *Msg_Tags) MarshalTo(dest []byte) error {
 	for _, val := range m.Tags {
 		enc.EncodeString(1, val)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -5,7 +5,6 @@ package googlev2
 
 import (
 	"fmt"
-	"strings"
 	"sync/atomic"
 	"github.com/CrowdStrike/csproto"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -120,7 +119,7 @@ func (m *BaseEvent) MarshalTo(dest []byte) error {
 		}
 	}
 
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -230,15 +229,7 @@ func (m *BaseEvent) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -383,7 +374,7 @@ func (m *TestEvent) MarshalTo(dest []byte) error {
 			_ = typedVal // ensure no unused variable
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -517,15 +508,7 @@ func (m *TestEvent) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -617,7 +600,7 @@ func (m *EmbeddedEvent) MarshalTo(dest []byte) error {
 	for _, val := range m.RandomThings {
 		enc.EncodeBytes(4, val)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -706,15 +689,7 @@ func (m *EmbeddedEvent) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -917,7 +892,7 @@ func (m *AllTheThings) MarshalTo(dest []byte) error {
 			return fmt.Errorf("unable to encode message data for field 'theMessage' (tag=18): %w", err)
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1130,15 +1105,7 @@ func (m *AllTheThings) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -1233,9 +1200,8 @@ func (m *RepeatAllTheThings) Size() int {
 	}
 	// TheMessages (message,repeated)
 	for _, val := range m.TheMessages {
-		if l = csproto.Size(val); l > 0 {
-			sz += csproto.SizeOfTagKey(18) + csproto.SizeOfVarint(uint64(l)) + l
-		}
+		l = csproto.Size(val)
+		sz += csproto.SizeOfTagKey(18) + csproto.SizeOfVarint(uint64(l)) + l
 	}
 	// cache the size so it can be re-used in Marshal()/MarshalTo()
 	atomic.StoreInt32(&m.sizeCache, int32(sz))
@@ -1346,7 +1312,7 @@ func (m *RepeatAllTheThings) MarshalTo(dest []byte) error {
 			return fmt.Errorf("unable to encode message data for field 'theMessages' (tag=18): %w", err)
 		}
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1654,15 +1620,7 @@ func (m *RepeatAllTheThings) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -1748,7 +1706,7 @@ func (m *EventUsingWKTs) MarshalTo(dest []byte) error {
 	if m.EventType != nil {
 		enc.EncodeInt32(3, int32(*m.EventType))
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
@@ -1820,15 +1778,7 @@ func (m *EventUsingWKTs) csprotoCheckRequiredFields() error {
 	}
 
 	if len(missingFields) > 0 {
-		var sb strings.Builder
-		sb.WriteString("cannot unmarshal, one or more required fields missing: ")
-		for i, s := range missingFields {
-			if i > 0 {
-				sb.WriteRune(',')
-			}
-			sb.WriteString(s)
-		}
-		return fmt.Errorf(sb.String())
+		return &csproto.RequiredFieldMissingError{Fields: missingFields}
 	}
 	return nil
 }
@@ -1894,7 +1844,7 @@ func (m *TestEvent_NestedMsg) MarshalTo(dest []byte) error {
 	if m.Details != nil {
 		enc.EncodeString(1, *m.Details)
 	}
-	return nil
+	return enc.Err()
 }
 
 // Unmarshal decodes a binary encoded Protobuf message from p and populates m with the result.
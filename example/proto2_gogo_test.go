@@ -62,6 +62,11 @@ func TestUnmarshalFailsOnMissingRequiredFieldForGogoMessage(t *testing.T) {
 	err := csproto.Unmarshal(data, &msg)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "one or more required fields missing")
+
+	var rfmErr *csproto.RequiredFieldMissingError
+	if assert.ErrorAs(t, err, &rfmErr) {
+		assert.Equal(t, []string{"EventID"}, rfmErr.Fields)
+	}
 }
 
 func TestProto2GogoMarshalJSON(t *testing.T) {
@@ -69,6 +69,11 @@ func TestUnmarshalFailsOnMissingRequiredFieldForGoogleV1Message(t *testing.T) {
 	err := csproto.Unmarshal(data, &msg)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "one or more required fields missing")
+
+	var rfmErr *csproto.RequiredFieldMissingError
+	if assert.ErrorAs(t, err, &rfmErr) {
+		assert.Equal(t, []string{"EventID"}, rfmErr.Fields)
+	}
 }
 
 func TestProto2GoogleV1MarshalJSON(t *testing.T) {
@@ -69,6 +69,11 @@ func TestUnmarshalFailsOnMissingRequiredFieldForGoogleV2Message(t *testing.T) {
 	err := csproto.Unmarshal(data, &msg)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "one or more required fields missing")
+
+	var rfmErr *csproto.RequiredFieldMissingError
+	if assert.ErrorAs(t, err, &rfmErr) {
+		assert.Equal(t, []string{"EventID"}, rfmErr.Fields)
+	}
 }
 
 func TestProto2GoogleV2MarshalJSON(t *testing.T) {
@@ -248,6 +253,65 @@ func TestProto2GoogleV2UnmarshalJSON(t *testing.T) {
 	})
 }
 
+func TestProto2GoogleV2UnmarshalJSONAllowPartialForRepeated(t *testing.T) {
+	t.Run("partial-repeated-element", func(t *testing.T) {
+		data := []byte(`{"ID":1,"theMessages":[{"ID":2,"stuff":"complete"},{"stuff":"partial"}]}`)
+		var msg googlev2.RepeatAllTheThings
+
+		opts := []csproto.JSONOption{
+			csproto.JSONAllowPartialForRepeated(true),
+		}
+		err := csproto.JSONUnmarshaler(&msg, opts...).UnmarshalJSON(data)
+		assert.NoError(t, err)
+		expected := googlev2.RepeatAllTheThings{
+			ID: csproto.Int32(1),
+			TheMessages: []*googlev2.EmbeddedEvent{
+				{ID: csproto.Int32(2), Stuff: csproto.String("complete")},
+				{Stuff: csproto.String("partial")},
+			},
+		}
+		assert.True(t, csproto.Equal(&msg, &expected))
+	})
+	t.Run("partial-repeated-element-without-option", func(t *testing.T) {
+		data := []byte(`{"ID":1,"theMessages":[{"stuff":"partial"}]}`)
+		var msg googlev2.RepeatAllTheThings
+
+		err := csproto.JSONUnmarshaler(&msg).UnmarshalJSON(data)
+		assert.Error(t, err, "JSON unmarshaling should fail if a repeated element is missing required fields")
+	})
+	t.Run("partial-top-level", func(t *testing.T) {
+		data := []byte(`{"theMessages":[{"ID":2}]}`)
+		var msg googlev2.RepeatAllTheThings
+
+		opts := []csproto.JSONOption{
+			csproto.JSONAllowPartialForRepeated(true),
+		}
+		err := csproto.JSONUnmarshaler(&msg, opts...).UnmarshalJSON(data)
+		assert.Error(t, err, "JSON unmarshaling should fail if top-level required fields are missing")
+	})
+	t.Run("partial-singular-nested-message", func(t *testing.T) {
+		data := []byte(`{"ID":1,"theMessage":{"stuff":"partial"}}`)
+		var msg googlev2.AllTheThings
+
+		opts := []csproto.JSONOption{
+			csproto.JSONAllowPartialForRepeated(true),
+		}
+		err := csproto.JSONUnmarshaler(&msg, opts...).UnmarshalJSON(data)
+		assert.Error(t, err, "JSON unmarshaling should fail if a non-repeated nested message is missing required fields")
+	})
+	t.Run("allow-partial-takes-precedence", func(t *testing.T) {
+		data := []byte(`{"theMessages":[{"stuff":"partial"}]}`)
+		var msg googlev2.RepeatAllTheThings
+
+		opts := []csproto.JSONOption{
+			csproto.JSONAllowPartialForRepeated(true),
+			csproto.JSONAllowPartialMessages(true),
+		}
+		err := csproto.JSONUnmarshaler(&msg, opts...).UnmarshalJSON(data)
+		assert.NoError(t, err)
+	})
+}
+
 func TestProto2GoogleV2MarshalText(t *testing.T) {
 	msg := createTestProto2GoogleV2Message()
 	// replace the current date/time with a known value for reproducible output
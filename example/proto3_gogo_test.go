@@ -48,6 +48,15 @@ func TestProto3GogoMessage(t *testing.T) {
 	})
 }
 
+func TestProto3GogoSizeWithEmptyRepeatedMessage(t *testing.T) {
+	msg := &gogo.RepeatAllTheThings{
+		TheMessages: []*gogo.EmbeddedEvent{{}, {ID: 1}, {}},
+	}
+	data, err := csproto.Marshal(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), csproto.Size(msg), "Size() should include empty repeated message elements")
+}
+
 func TestProto3GogoMarshalJSON(t *testing.T) {
 	t.Run("default", func(t *testing.T) {
 		ts := types.TimestampNow()
@@ -49,6 +49,15 @@ func TestProto3GoogleV1Message(t *testing.T) {
 	})
 }
 
+func TestProto3GoogleV1SizeWithEmptyRepeatedMessage(t *testing.T) {
+	msg := &googlev1.RepeatAllTheThings{
+		TheMessages: []*googlev1.EmbeddedEvent{{}, {ID: 1}, {}},
+	}
+	data, err := csproto.Marshal(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), csproto.Size(msg), "Size() should include empty repeated message elements")
+}
+
 func TestProto3GoogleV1MarshalJSON(t *testing.T) {
 	t.Run("default", func(t *testing.T) {
 		ts := timestamppb.Now()
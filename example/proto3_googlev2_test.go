@@ -1,7 +1,10 @@
 package example_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"testing"
@@ -12,12 +15,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/CrowdStrike/csproto"
+	permessagev2 "github.com/CrowdStrike/csproto/example/permessage/googlev2"
 	"github.com/CrowdStrike/csproto/example/proto3/googlev2"
+	"github.com/CrowdStrike/csproto/lazyproto"
 )
 
 func TestProto3GoogleV2Message(t *testing.T) {
@@ -54,6 +60,22 @@ func TestProto3GoogleV2Message(t *testing.T) {
 	})
 }
 
+func TestProto3GoogleV2SizeWithEmptyRepeatedMessage(t *testing.T) {
+	msg := &googlev2.RepeatAllTheThings{
+		TheMessages: []*googlev2.EmbeddedEvent{{}, {ID: 1}, {}},
+	}
+	data, err := csproto.Marshal(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), csproto.Size(msg), "Size() should include empty repeated message elements")
+}
+
+func TestProto3GoogleV2MarshalToBufferTooSmall(t *testing.T) {
+	msg := createTestProto3GoogleV2Message()
+	buf := make([]byte, csproto.Size(msg)-1)
+	err := msg.MarshalTo(buf)
+	assert.ErrorIs(t, err, csproto.ErrBufferTooSmall)
+}
+
 func TestProto3GoogleV2MarshalJSON(t *testing.T) {
 	t.Run("default", func(t *testing.T) {
 		ts := timestamppb.Now()
@@ -500,6 +522,453 @@ func TestProto3GoogleV2Maps(t *testing.T) {
 	}
 }
 
+func TestProto3GoogleV2Transform(t *testing.T) {
+	t.Run("string normalization", func(t *testing.T) {
+		msg := &googlev2.AllTheThings{ID: 1, TheString: "Hello, World!"}
+		got := csproto.Transform(msg, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+			if fd.Kind() == protoreflect.StringKind {
+				return protoreflect.ValueOfString(strings.ToLower(v.String()))
+			}
+			return v
+		})
+		expected := &googlev2.AllTheThings{ID: 1, TheString: "hello, world!"}
+		if diff := cmp.Diff(expected, got, protocmp.Transform()); diff != "" {
+			t.Errorf("unexpected difference:\n%v", diff)
+		}
+		assert.Equal(t, "Hello, World!", msg.TheString, "original message should not be modified")
+	})
+	t.Run("numeric clamping", func(t *testing.T) {
+		msg := &googlev2.AllTheThings{TheInt32: 1138, TheInt64: -1138, TheUInt32: 42}
+		got := csproto.Transform(msg, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+			switch fd.Kind() { //nolint: exhaustive // only clamping signed integers
+			case protoreflect.Int32Kind:
+				return protoreflect.ValueOfInt32(int32(clamp(v.Int(), -100, 100)))
+			case protoreflect.Int64Kind:
+				return protoreflect.ValueOfInt64(clamp(v.Int(), -100, 100))
+			default:
+				return v
+			}
+		})
+		expected := &googlev2.AllTheThings{TheInt32: 100, TheInt64: -100, TheUInt32: 42}
+		if diff := cmp.Diff(expected, got, protocmp.Transform()); diff != "" {
+			t.Errorf("unexpected difference:\n%v", diff)
+		}
+	})
+	t.Run("bytes redaction", func(t *testing.T) {
+		msg := &googlev2.AllTheThings{ID: 1, TheBytes: []byte("super secret")}
+		got := csproto.Transform(msg, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+			if fd.Kind() == protoreflect.BytesKind {
+				return protoreflect.Value{}
+			}
+			return v
+		})
+		expected := &googlev2.AllTheThings{ID: 1}
+		if diff := cmp.Diff(expected, got, protocmp.Transform()); diff != "" {
+			t.Errorf("unexpected difference:\n%v", diff)
+		}
+		assert.Equal(t, []byte("super secret"), msg.TheBytes, "original message should not be modified")
+	})
+	t.Run("no-op", func(t *testing.T) {
+		msg := createTestProto3GoogleV2Message()
+		got := csproto.Transform(msg, func(_ protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+			return v
+		})
+		assert.True(t, csproto.Equal(msg, got), "messages should be equal\nm1=%s\nm2=%s", msg.String(), got)
+		assert.NotEqual(t, unsafe.Pointer(msg), unsafe.Pointer(got.(*googlev2.TestEvent)))
+	})
+	t.Run("nil message", func(t *testing.T) {
+		got := csproto.Transform(nil, func(_ protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+			return v
+		})
+		assert.Nil(t, got)
+	})
+}
+
+func TestProto3GoogleV2GetField(t *testing.T) {
+	msg := &googlev2.AllTheThings{
+		ID:           1,
+		TheString:    "testing",
+		TheBool:      true,
+		TheInt32:     -32,
+		TheInt64:     -64,
+		TheUInt32:    32,
+		TheUInt64:    64,
+		TheSInt32:    -132,
+		TheSInt64:    -164,
+		TheFixed32:   232,
+		TheFixed64:   264,
+		TheSFixed32:  -332,
+		TheSFixed64:  -364,
+		TheFloat:     3.14,
+		TheDouble:    2.718,
+		TheEventType: googlev2.EventType_EVENT_TYPE_TWO,
+		TheBytes:     []byte("bytes"),
+		TheMessage:   &googlev2.EmbeddedEvent{ID: 42, Stuff: "nested"},
+	}
+	cases := []struct {
+		name        string
+		fieldNumber int32
+		expected    interface{}
+	}{
+		{name: "string", fieldNumber: 2, expected: "testing"},
+		{name: "bool", fieldNumber: 3, expected: true},
+		{name: "int32", fieldNumber: 4, expected: int32(-32)},
+		{name: "int64", fieldNumber: 5, expected: int64(-64)},
+		{name: "uint32", fieldNumber: 6, expected: uint32(32)},
+		{name: "uint64", fieldNumber: 7, expected: uint64(64)},
+		{name: "sint32", fieldNumber: 8, expected: int32(-132)},
+		{name: "sint64", fieldNumber: 9, expected: int64(-164)},
+		{name: "fixed32", fieldNumber: 10, expected: uint32(232)},
+		{name: "fixed64", fieldNumber: 11, expected: uint64(264)},
+		{name: "sfixed32", fieldNumber: 12, expected: int32(-332)},
+		{name: "sfixed64", fieldNumber: 13, expected: int64(-364)},
+		{name: "float", fieldNumber: 14, expected: float32(3.14)},
+		{name: "double", fieldNumber: 15, expected: float64(2.718)},
+		{name: "enum", fieldNumber: 16, expected: protoreflect.EnumNumber(googlev2.EventType_EVENT_TYPE_TWO)},
+		{name: "bytes", fieldNumber: 17, expected: []byte("bytes")},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := csproto.GetField(msg, tc.fieldNumber)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, v.Interface())
+		})
+	}
+	t.Run("nested message", func(t *testing.T) {
+		v, err := csproto.GetField(msg, 18)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(msg.TheMessage, v.Message().Interface()))
+
+		id, err := csproto.GetField(v.Message().Interface(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(42), int32(id.Int()))
+	})
+	t.Run("repeated field", func(t *testing.T) {
+		rmsg := &googlev2.RepeatAllTheThings{TheStrings: []string{"one", "two"}}
+		v, err := csproto.GetField(rmsg, 2)
+		assert.NoError(t, err)
+		list := v.List()
+		if assert.Equal(t, 2, list.Len()) {
+			assert.Equal(t, "one", list.Get(0).String())
+			assert.Equal(t, "two", list.Get(1).String())
+		}
+	})
+	t.Run("unset field returns the default value", func(t *testing.T) {
+		v, err := csproto.GetField(&googlev2.AllTheThings{}, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "", v.String())
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := csproto.GetField(msg, 1138)
+		assert.ErrorIs(t, err, csproto.ErrFieldNotFound)
+	})
+	t.Run("nil message", func(t *testing.T) {
+		_, err := csproto.GetField(nil, 1)
+		assert.ErrorIs(t, err, csproto.ErrFieldNotFound)
+	})
+}
+
+func TestProto3GoogleV2ClearField(t *testing.T) {
+	newMsg := func() *googlev2.AllTheThings {
+		return &googlev2.AllTheThings{
+			ID:           1,
+			TheString:    "testing",
+			TheBool:      true,
+			TheInt32:     -32,
+			TheInt64:     -64,
+			TheUInt32:    32,
+			TheUInt64:    64,
+			TheSInt32:    -132,
+			TheSInt64:    -164,
+			TheFixed32:   232,
+			TheFixed64:   264,
+			TheSFixed32:  -332,
+			TheSFixed64:  -364,
+			TheFloat:     3.14,
+			TheDouble:    2.718,
+			TheEventType: googlev2.EventType_EVENT_TYPE_TWO,
+			TheBytes:     []byte("bytes"),
+			TheMessage:   &googlev2.EmbeddedEvent{ID: 42, Stuff: "nested"},
+		}
+	}
+	cases := []struct {
+		name        string
+		fieldNumber int32
+		clear       func(*googlev2.AllTheThings)
+	}{
+		{name: "string", fieldNumber: 2, clear: func(m *googlev2.AllTheThings) { m.TheString = "" }},
+		{name: "bool", fieldNumber: 3, clear: func(m *googlev2.AllTheThings) { m.TheBool = false }},
+		{name: "int32", fieldNumber: 4, clear: func(m *googlev2.AllTheThings) { m.TheInt32 = 0 }},
+		{name: "int64", fieldNumber: 5, clear: func(m *googlev2.AllTheThings) { m.TheInt64 = 0 }},
+		{name: "uint32", fieldNumber: 6, clear: func(m *googlev2.AllTheThings) { m.TheUInt32 = 0 }},
+		{name: "uint64", fieldNumber: 7, clear: func(m *googlev2.AllTheThings) { m.TheUInt64 = 0 }},
+		{name: "sint32", fieldNumber: 8, clear: func(m *googlev2.AllTheThings) { m.TheSInt32 = 0 }},
+		{name: "sint64", fieldNumber: 9, clear: func(m *googlev2.AllTheThings) { m.TheSInt64 = 0 }},
+		{name: "fixed32", fieldNumber: 10, clear: func(m *googlev2.AllTheThings) { m.TheFixed32 = 0 }},
+		{name: "fixed64", fieldNumber: 11, clear: func(m *googlev2.AllTheThings) { m.TheFixed64 = 0 }},
+		{name: "sfixed32", fieldNumber: 12, clear: func(m *googlev2.AllTheThings) { m.TheSFixed32 = 0 }},
+		{name: "sfixed64", fieldNumber: 13, clear: func(m *googlev2.AllTheThings) { m.TheSFixed64 = 0 }},
+		{name: "float", fieldNumber: 14, clear: func(m *googlev2.AllTheThings) { m.TheFloat = 0 }},
+		{name: "double", fieldNumber: 15, clear: func(m *googlev2.AllTheThings) { m.TheDouble = 0 }},
+		{name: "enum", fieldNumber: 16, clear: func(m *googlev2.AllTheThings) { m.TheEventType = googlev2.EventType_EVENT_TYPE_UNDEFINED }},
+		{name: "bytes", fieldNumber: 17, clear: func(m *googlev2.AllTheThings) { m.TheBytes = nil }},
+		{name: "nested message", fieldNumber: 18, clear: func(m *googlev2.AllTheThings) { m.TheMessage = nil }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			msg, expected := newMsg(), newMsg()
+			tc.clear(expected)
+
+			err := csproto.ClearField(msg, tc.fieldNumber)
+			assert.NoError(t, err)
+			if diff := cmp.Diff(expected, msg, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected difference:\n%v", diff)
+			}
+		})
+	}
+	t.Run("nested message becomes nil", func(t *testing.T) {
+		msg := newMsg()
+		assert.NoError(t, csproto.ClearField(msg, 18))
+		assert.Nil(t, msg.TheMessage)
+	})
+	t.Run("repeated field", func(t *testing.T) {
+		msg := &googlev2.RepeatAllTheThings{ID: 1, TheStrings: []string{"one", "two"}, TheInt32S: []int32{1, 2}}
+		assert.NoError(t, csproto.ClearField(msg, 2))
+		assert.Empty(t, msg.TheStrings)
+		assert.Equal(t, []int32{1, 2}, msg.TheInt32S, "other fields should not be modified")
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		err := csproto.ClearField(newMsg(), 1138)
+		assert.ErrorIs(t, err, csproto.ErrFieldNotFound)
+	})
+	t.Run("read-only message", func(t *testing.T) {
+		assert.ErrorIs(t, csproto.ClearField((*googlev2.AllTheThings)(nil), 2), csproto.ErrReadOnlyMessage)
+		assert.ErrorIs(t, csproto.ClearField(nil, 2), csproto.ErrReadOnlyMessage)
+	})
+}
+
+func TestProto3GoogleV2MessageToMap(t *testing.T) {
+	t.Run("all scalar types", func(t *testing.T) {
+		msg := googlev2.AllTheThings{
+			ID:           1,
+			TheString:    "testing",
+			TheBool:      true,
+			TheInt32:     -32,
+			TheInt64:     -64,
+			TheUInt32:    32,
+			TheUInt64:    64,
+			TheSInt32:    -132,
+			TheSInt64:    -164,
+			TheFixed32:   232,
+			TheFixed64:   264,
+			TheSFixed32:  -332,
+			TheSFixed64:  -364,
+			TheFloat:     3.14,
+			TheDouble:    2.718,
+			TheEventType: googlev2.EventType_EVENT_TYPE_TWO,
+			TheBytes:     []byte("bytes"),
+			TheMessage:   &googlev2.EmbeddedEvent{ID: 42, Stuff: "nested"},
+		}
+		expected := map[string]interface{}{
+			"ID":           int32(1),
+			"theString":    "testing",
+			"theBool":      true,
+			"theInt32":     int32(-32),
+			"theInt64":     int64(-64),
+			"theUInt32":    uint32(32),
+			"theUInt64":    uint64(64),
+			"theSInt32":    int32(-132),
+			"theSInt64":    int64(-164),
+			"theFixed32":   uint32(232),
+			"theFixed64":   uint64(264),
+			"theSFixed32":  int32(-332),
+			"theSFixed64":  int64(-364),
+			"theFloat":     float32(3.14),
+			"theDouble":    2.718,
+			"theEventType": int32(googlev2.EventType_EVENT_TYPE_TWO),
+			"theBytes":     []byte("bytes"),
+			"theMessage": map[string]interface{}{
+				"ID":    int32(42),
+				"stuff": "nested",
+			},
+		}
+
+		got, err := csproto.MessageToMap(&msg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+	t.Run("unpopulated fields are omitted", func(t *testing.T) {
+		got, err := csproto.MessageToMap(&googlev2.AllTheThings{TheString: "testing"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"theString": "testing"}, got)
+	})
+	t.Run("repeated fields", func(t *testing.T) {
+		msg := googlev2.RepeatAllTheThings{
+			TheStrings:    []string{"a", "b"},
+			TheInt32S:     []int32{1, -1},
+			TheFloats:     []float32{1.5},
+			TheEventTypes: []googlev2.EventType{googlev2.EventType_EVENT_TYPE_ONE, googlev2.EventType_EVENT_TYPE_TWO},
+			TheMessages: []*googlev2.EmbeddedEvent{
+				{ID: 1},
+				{ID: 2, FavoriteNumbers: []int32{7}},
+			},
+		}
+		expected := map[string]interface{}{
+			"theStrings":    []interface{}{"a", "b"},
+			"theInt32s":     []interface{}{int32(1), int32(-1)},
+			"theFloats":     []interface{}{float32(1.5)},
+			"theEventTypes": []interface{}{int32(1), int32(2)},
+			"theMessages": []interface{}{
+				map[string]interface{}{"ID": int32(1)},
+				map[string]interface{}{"ID": int32(2), "favoriteNumbers": []interface{}{int32(7)}},
+			},
+		}
+
+		got, err := csproto.MessageToMap(&msg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+	t.Run("map fields", func(t *testing.T) {
+		msg := permessagev2.AllTheMaps{
+			ToInt32:   map[string]int32{"one": 1},
+			ToMessage: map[string]*permessagev2.EmbeddedEvent{"evt": {ID: 1, Stuff: "stuff"}},
+			ToEnum:    map[string]permessagev2.EventType{"two": permessagev2.EventType_EVENT_TYPE_TWO},
+		}
+		expected := map[string]interface{}{
+			"toInt32":   map[string]interface{}{"one": int32(1)},
+			"toMessage": map[string]interface{}{"evt": map[string]interface{}{"ID": int32(1), "stuff": "stuff"}},
+			"toEnum":    map[string]interface{}{"two": int32(2)},
+		}
+
+		got, err := csproto.MessageToMap(&msg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+	t.Run("json serializable", func(t *testing.T) {
+		msg := googlev2.RepeatAllTheThings{
+			ID:          1,
+			TheStrings:  []string{"a"},
+			TheMessages: []*googlev2.EmbeddedEvent{{ID: 2, Stuff: "nested"}},
+		}
+		got, err := csproto.MessageToMap(&msg)
+		require.NoError(t, err)
+		data, err := json.Marshal(got)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"ID":1,"theStrings":["a"],"theMessages":[{"ID":2,"stuff":"nested"}]}`, string(data))
+	})
+	t.Run("nil message", func(t *testing.T) {
+		_, err := csproto.MessageToMap(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestProto3GoogleV2LazyDefFromDescriptor(t *testing.T) {
+	msg := googlev2.AllTheThings{
+		ID:         1,
+		TheString:  "testing",
+		TheMessage: &googlev2.EmbeddedEvent{ID: 42, Stuff: "nested"},
+	}
+	expected := lazyproto.NewDef(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17)
+	expected.NestedTag(18, 1, 2, 3, 4)
+
+	def, err := lazyproto.DefFromDescriptor(msg.ProtoReflect().Descriptor(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, def)
+
+	data, err := proto.Marshal(&msg)
+	require.NoError(t, err)
+	res, err := lazyproto.Decode(data, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+	fd, err := res.FieldData(18, 2)
+	require.NoError(t, err)
+	v, err := fd.StringValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "nested", v)
+}
+
+func TestProto3GoogleV2JSONLines(t *testing.T) {
+	const count = 10
+	var msgs []*googlev2.TestEvent
+	for i := 0; i < count; i++ {
+		msgs = append(msgs, &googlev2.TestEvent{
+			Name:   fmt.Sprintf("event %d", i),
+			Info:   "multi\nline\ninfo",
+			Labels: []string{"a", "b"},
+			Embedded: &googlev2.EmbeddedEvent{
+				ID:    int32(i),
+				Stuff: "nested",
+			},
+		})
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		// JSONIndent() is ignored so that each message is written on a single line
+		enc := csproto.NewJSONLineEncoder(pw, csproto.JSONIndent("  "))
+		for _, msg := range msgs {
+			if err := enc.Encode(msg); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		_ = pw.CloseWithError(enc.Close())
+	}()
+
+	// capture the raw output while decoding to verify the line structure
+	var raw strings.Builder
+	dec := csproto.NewJSONLineDecoder(io.TeeReader(pr, &raw))
+	for i := 0; ; i++ {
+		var msg googlev2.TestEvent
+		err := dec.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			assert.Equal(t, count, i, "should have decoded all of the messages")
+			break
+		}
+		require.NoError(t, err)
+		require.Less(t, i, count)
+		assert.True(t, proto.Equal(msgs[i], &msg), "message %d does not match", i)
+	}
+	lines := strings.Split(strings.TrimSuffix(raw.String(), "\n"), "\n")
+	assert.Len(t, lines, count, "each message should be written on a single line")
+	for _, l := range lines {
+		assert.True(t, json.Valid([]byte(l)), "each line should be valid JSON")
+	}
+
+	t.Run("blank lines and missing final newline", func(t *testing.T) {
+		data := "\n{\"name\":\"one\"}\n\n{\"name\":\"two\"}"
+		dec := csproto.NewJSONLineDecoder(strings.NewReader(data))
+		var names []string
+		for {
+			var msg googlev2.TestEvent
+			err := dec.Decode(&msg)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, msg.GetName())
+		}
+		assert.Equal(t, []string{"one", "two"}, names)
+	})
+	t.Run("nil message", func(t *testing.T) {
+		enc := csproto.NewJSONLineEncoder(io.Discard)
+		assert.Error(t, enc.Encode((*googlev2.TestEvent)(nil)))
+	})
+}
+
+func clamp(v, lo, hi int64) int64 {
+	switch {
+	case v < lo:
+		return lo
+	case v > hi:
+		return hi
+	default:
+		return v
+	}
+}
+
 func createTestProto3GoogleV2Message() *googlev2.TestEvent {
 	event := googlev2.TestEvent{
 		Name:   "test",
@@ -0,0 +1,156 @@
+package csproto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	googlev2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ErrInvalidFieldMaskPath is returned by FieldMaskFromProto() when a path in the source field mask
+// cannot be resolved against the message descriptor.
+var ErrInvalidFieldMaskPath = errors.New("invalid field mask path")
+
+// FieldMask is a lightweight field mask that identifies a set of message fields by their tag "paths"
+// rather than by name.  Each entry is the sequence of field tags leading from the root message to
+// a selected field, so {5, 1} selects field 1 of the nested message at field 5.  Selecting a message
+// field without any nested tags selects the entire nested message.
+//
+// Unlike [fieldmaskpb.FieldMask], a FieldMask does not require a message descriptor to be applied,
+// which allows it to be used for both marshaling and partial decoding.
+type FieldMask [][]int
+
+// FieldMaskFromProto converts m into a FieldMask by resolving each of its dot-separated field name
+// paths against desc.
+//
+// An error wrapping ErrInvalidFieldMaskPath is returned if any path refers to a field that does not
+// exist or traverses through a field that is not a singular message field.  If m is nil, this function
+// returns nil.
+func FieldMaskFromProto(m *fieldmaskpb.FieldMask, desc protoreflect.MessageDescriptor) (FieldMask, error) {
+	if m == nil {
+		return nil, nil
+	}
+	mask := make(FieldMask, 0, len(m.GetPaths()))
+	for _, p := range m.GetPaths() {
+		var (
+			names = strings.Split(p, ".")
+			tags  = make([]int, 0, len(names))
+			md    = desc
+		)
+		for i, name := range names {
+			fd := md.Fields().ByName(protoreflect.Name(name))
+			if fd == nil {
+				return nil, fmt.Errorf("%w: %q has no field %q in message %s", ErrInvalidFieldMaskPath, p, name, md.FullName())
+			}
+			tags = append(tags, int(fd.Number()))
+			if i == len(names)-1 {
+				break
+			}
+			if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+				return nil, fmt.Errorf("%w: %q traverses field %q, which is not a singular message field", ErrInvalidFieldMaskPath, p, name)
+			}
+			md = fd.Message()
+		}
+		mask = append(mask, tags)
+	}
+	return mask, nil
+}
+
+// fieldMaskTree is the tree representation of a FieldMask used when applying the mask to a message.
+// A nil subtree indicates that the entire field is selected.
+type fieldMaskTree map[int]fieldMaskTree
+
+// tree converts m into a fieldMaskTree
+func (m FieldMask) tree() fieldMaskTree {
+	root := fieldMaskTree{}
+	for _, path := range m {
+		t := root
+		for i, tag := range path {
+			if i == len(path)-1 {
+				t[tag] = nil
+				break
+			}
+			sub, exists := t[tag]
+			if exists && sub == nil {
+				// the entire field has already been selected
+				break
+			}
+			if sub == nil {
+				sub = fieldMaskTree{}
+				t[tag] = sub
+			}
+			t = sub
+		}
+	}
+	return root
+}
+
+// Filter clears all fields of msg that are not selected by mask, recursing into nested messages for
+// paths that select individual fields of a nested message.  Unknown fields are also discarded.
+//
+// Unlike marshaling with WithFieldMask(), Filter modifies msg in place rather than operating on a copy,
+// so callers that need to retain the original data must clone the message first.  As with
+// WithFieldMask(), an empty mask is ignored and leaves msg unchanged.
+//
+// An error is returned if msg is nil or read-only.
+func Filter(msg googlev2.Message, mask FieldMask) error {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return ErrReadOnlyMessage
+	}
+	if len(mask) == 0 {
+		return nil
+	}
+	applyFieldMaskTree(msg.ProtoReflect(), mask.tree())
+	return nil
+}
+
+// applyFieldMask returns a copy of msg with all fields not selected by mask cleared.
+//
+// Field masks require Protobuf reflection so only Google V1 and V2 messages are supported.
+func applyFieldMask(msg interface{}, mask FieldMask) (googlev2.Message, error) {
+	var pm googlev2.Message
+	switch MsgType(msg) {
+	case MessageTypeGoogle:
+		pm = msg.(googlev2.Message)
+	case MessageTypeGoogleV1:
+		if m1, ok := msg.(protoadapt.MessageV1); ok {
+			pm = protoadapt.MessageV2Of(m1)
+		}
+	default:
+	}
+	if pm == nil {
+		return nil, fmt.Errorf("field masks are not supported for messages of type %T", msg)
+	}
+	res := googlev2.Clone(pm)
+	applyFieldMaskTree(res.ProtoReflect(), mask.tree())
+	return res, nil
+}
+
+// applyFieldMaskTree clears all fields of m that are not selected by t, recursing into nested messages
+func applyFieldMaskTree(m protoreflect.Message, t fieldMaskTree) {
+	// collect the fields before making any changes since modifying a message while iterating
+	// over it with Range() is undefined behavior
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		sub, selected := t[int(fd.Number())]
+		switch {
+		case !selected:
+			m.Clear(fd)
+		case sub != nil && fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			applyFieldMaskTree(m.Mutable(fd).Message(), sub)
+		default:
+			// keep the entire field
+		}
+	}
+	if len(m.GetUnknown()) > 0 {
+		m.SetUnknown(nil)
+	}
+}
@@ -0,0 +1,232 @@
+package csproto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+func TestFieldMaskFromProto(t *testing.T) {
+	t.Parallel()
+	desc := (&descriptorpb.FileDescriptorProto{}).ProtoReflect().Descriptor()
+	cases := []struct {
+		name      string
+		paths     []string
+		expected  csproto.FieldMask
+		expectErr bool
+	}{
+		{
+			name:     "empty mask",
+			paths:    []string{},
+			expected: csproto.FieldMask{},
+		},
+		{
+			name:     "top-level fields",
+			paths:    []string{"name", "package", "syntax"},
+			expected: csproto.FieldMask{{1}, {2}, {12}},
+		},
+		{
+			name:     "nested fields",
+			paths:    []string{"name", "options.java_package", "options.go_package"},
+			expected: csproto.FieldMask{{1}, {8, 1}, {8, 11}},
+		},
+		{
+			name:      "unknown field",
+			paths:     []string{"name", "not_a_field"},
+			expectErr: true,
+		},
+		{
+			name:      "unknown nested field",
+			paths:     []string{"options.not_a_field"},
+			expectErr: true,
+		},
+		{
+			name:      "path through scalar field",
+			paths:     []string{"name.foo"},
+			expectErr: true,
+		},
+		{
+			name:      "path through repeated field",
+			paths:     []string{"message_type.name"},
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mask, err := csproto.FieldMaskFromProto(&fieldmaskpb.FieldMask{Paths: tc.paths}, desc)
+			if tc.expectErr {
+				assert.ErrorIs(t, err, csproto.ErrInvalidFieldMaskPath)
+				assert.Nil(t, mask)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, mask)
+		})
+	}
+	t.Run("nil mask", func(t *testing.T) {
+		t.Parallel()
+		mask, err := csproto.FieldMaskFromProto(nil, desc)
+		assert.NoError(t, err)
+		assert.Nil(t, mask)
+	})
+}
+
+func TestMarshalWithFieldMask(t *testing.T) {
+	t.Parallel()
+	newMsg := func() *descriptorpb.FileDescriptorProto {
+		return &descriptorpb.FileDescriptorProto{
+			Name:       proto.String("test.proto"),
+			Package:    proto.String("csproto.test"),
+			Dependency: []string{"google/protobuf/timestamp.proto"},
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("Foo")},
+			},
+			Options: &descriptorpb.FileOptions{
+				JavaPackage: proto.String("com.crowdstrike.csproto"),
+				GoPackage:   proto.String("github.com/CrowdStrike/csproto"),
+			},
+			Syntax: proto.String("proto3"),
+		}
+	}
+	cases := []struct {
+		name     string
+		mask     csproto.FieldMask
+		expected *descriptorpb.FileDescriptorProto
+	}{
+		{
+			name:     "empty mask",
+			mask:     nil,
+			expected: newMsg(),
+		},
+		{
+			name: "top-level fields",
+			mask: csproto.FieldMask{{1}, {3}},
+			expected: &descriptorpb.FileDescriptorProto{
+				Name:       proto.String("test.proto"),
+				Dependency: []string{"google/protobuf/timestamp.proto"},
+			},
+		},
+		{
+			name: "entire nested message",
+			mask: csproto.FieldMask{{8}},
+			expected: &descriptorpb.FileDescriptorProto{
+				Options: newMsg().Options,
+			},
+		},
+		{
+			name: "nested fields",
+			mask: csproto.FieldMask{{1}, {8, 11}},
+			expected: &descriptorpb.FileDescriptorProto{
+				Name: proto.String("test.proto"),
+				Options: &descriptorpb.FileOptions{
+					GoPackage: proto.String("github.com/CrowdStrike/csproto"),
+				},
+			},
+		},
+		{
+			name: "entire nested message wins over nested fields",
+			mask: csproto.FieldMask{{8, 11}, {8}},
+			expected: &descriptorpb.FileDescriptorProto{
+				Options: newMsg().Options,
+			},
+		},
+		{
+			name:     "unset fields",
+			mask:     csproto.FieldMask{{5}, {6}},
+			expected: &descriptorpb.FileDescriptorProto{},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			msg := newMsg()
+
+			data, err := csproto.Marshal(msg, csproto.WithFieldMask(tc.mask))
+			assert.NoError(t, err)
+
+			var got descriptorpb.FileDescriptorProto
+			assert.NoError(t, csproto.Unmarshal(data, &got))
+			assert.True(t, proto.Equal(tc.expected, &got), "expected %v, got %v", tc.expected, &got)
+			assert.True(t, proto.Equal(newMsg(), msg), "the source message should not be modified")
+		})
+	}
+	t.Run("unsupported message type", func(t *testing.T) {
+		t.Parallel()
+		_, err := csproto.Marshal(struct{}{}, csproto.WithFieldMask(csproto.FieldMask{{1}}))
+		assert.Error(t, err)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	newMsg := func() *descriptorpb.FileDescriptorProto {
+		return &descriptorpb.FileDescriptorProto{
+			Name:       proto.String("test.proto"),
+			Package:    proto.String("csproto.test"),
+			Dependency: []string{"google/protobuf/timestamp.proto"},
+			Options: &descriptorpb.FileOptions{
+				JavaPackage: proto.String("com.crowdstrike.csproto"),
+				GoPackage:   proto.String("github.com/CrowdStrike/csproto"),
+			},
+			Syntax: proto.String("proto3"),
+		}
+	}
+	cases := []struct {
+		name     string
+		mask     csproto.FieldMask
+		expected *descriptorpb.FileDescriptorProto
+	}{
+		{
+			name:     "empty mask",
+			mask:     nil,
+			expected: newMsg(),
+		},
+		{
+			name: "top-level fields",
+			mask: csproto.FieldMask{{1}, {3}},
+			expected: &descriptorpb.FileDescriptorProto{
+				Name:       proto.String("test.proto"),
+				Dependency: []string{"google/protobuf/timestamp.proto"},
+			},
+		},
+		{
+			name: "nested fields",
+			mask: csproto.FieldMask{{1}, {8, 11}},
+			expected: &descriptorpb.FileDescriptorProto{
+				Name: proto.String("test.proto"),
+				Options: &descriptorpb.FileOptions{
+					GoPackage: proto.String("github.com/CrowdStrike/csproto"),
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			masked, err := csproto.Marshal(newMsg(), csproto.WithFieldMask(tc.mask))
+			assert.NoError(t, err)
+
+			msg := newMsg()
+			assert.NoError(t, csproto.Filter(msg, tc.mask))
+			assert.True(t, proto.Equal(tc.expected, msg), "expected %v, got %v", tc.expected, msg)
+
+			filtered, err := csproto.Marshal(msg)
+			assert.NoError(t, err)
+			assert.Equal(t, masked, filtered, "Filter() then Marshal() should match marshaling with the same mask")
+		})
+	}
+	t.Run("nil message", func(t *testing.T) {
+		t.Parallel()
+		var msg *descriptorpb.FileDescriptorProto
+		assert.ErrorIs(t, csproto.Filter(msg, csproto.FieldMask{{1}}), csproto.ErrReadOnlyMessage)
+	})
+}
@@ -0,0 +1,146 @@
+package csproto
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	// ErrFieldNotFound is returned by GetField() and ClearField() when the message does not define a
+	// field with the specified field number.
+	ErrFieldNotFound = errors.New("field not found")
+	// ErrReadOnlyMessage is returned by ClearField() and Filter() when the message cannot be modified, such as
+	// a nil message.
+	ErrReadOnlyMessage = errors.New("message is read-only")
+)
+
+// GetField returns the value of the field with the specified field number in msg.
+//
+// As with [protoreflect.Message.Get], the default value for the field is returned if it is not set,
+// and the returned value for repeated, map, and message fields must be treated as read-only.  An error
+// that wraps ErrFieldNotFound is returned if the message does not define the field.
+func GetField(msg proto.Message, fieldNumber int32) (protoreflect.Value, error) {
+	m, fd, err := lookupField(msg, fieldNumber)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	return m.Get(fd), nil
+}
+
+// ClearField clears the field with the specified field number in msg so that it is no longer populated.
+// Scalar fields are reset to their default values, repeated and map fields become empty, and message
+// fields become nil.
+//
+// An error that wraps ErrFieldNotFound is returned if the message does not define the field and
+// ErrReadOnlyMessage is returned if msg is nil.
+func ClearField(msg proto.Message, fieldNumber int32) error {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return ErrReadOnlyMessage
+	}
+	m, fd, err := lookupField(msg, fieldNumber)
+	if err != nil {
+		return err
+	}
+	m.Clear(fd)
+	return nil
+}
+
+// lookupField returns the reflection wrapper for msg and the descriptor for the field with the
+// specified field number.
+func lookupField(msg proto.Message, fieldNumber int32) (protoreflect.Message, protoreflect.FieldDescriptor, error) {
+	if msg == nil {
+		return nil, nil, fmt.Errorf("%w: field %d of a nil message", ErrFieldNotFound, fieldNumber)
+	}
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByNumber(protoreflect.FieldNumber(fieldNumber))
+	if fd == nil {
+		return nil, nil, fmt.Errorf("%w: message %s has no field %d", ErrFieldNotFound, m.Descriptor().FullName(), fieldNumber)
+	}
+	return m, fd, nil
+}
+
+// MessageToMap converts msg to a generic map keyed by the JSON names of the populated fields, which is
+// useful for further processing that works with dynamic, JSON-like data.
+//
+// Scalar fields are converted to their natural Go types, enums to int32, nested messages to
+// map[string]interface{}, repeated fields to []interface{}, and map fields to map[string]interface{}
+// keyed by the string representation of the map keys.  Extension fields are keyed by their full name
+// in brackets, as with protojson.
+func MessageToMap(msg proto.Message) (map[string]interface{}, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("cannot convert a nil message to a map")
+	}
+	return messageToMap(msg.ProtoReflect())
+}
+
+// messageToMap is the internal implementation of MessageToMap() that converts the populated fields
+// of m.
+func messageToMap(m protoreflect.Message) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := fd.JSONName()
+		if fd.IsExtension() {
+			name = "[" + string(fd.FullName()) + "]"
+		}
+		switch {
+		case fd.IsList():
+			l := v.List()
+			vals := make([]interface{}, l.Len())
+			for i := 0; i < l.Len(); i++ {
+				if vals[i], err = fieldValueToInterface(fd, l.Get(i)); err != nil {
+					return false
+				}
+			}
+			res[name] = vals
+		case fd.IsMap():
+			vals := make(map[string]interface{}, v.Map().Len())
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				vals[k.String()], err = fieldValueToInterface(fd.MapValue(), mv)
+				return err == nil
+			})
+			res[name] = vals
+		default:
+			res[name], err = fieldValueToInterface(fd, v)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// fieldValueToInterface converts a single (non-repeated) value of the field described by fd to its
+// natural Go type.
+func fieldValueToInterface(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool(), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return int32(v.Int()), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int(), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return uint32(v.Uint()), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint(), nil
+	case protoreflect.FloatKind:
+		return float32(v.Float()), nil
+	case protoreflect.DoubleKind:
+		return v.Float(), nil
+	case protoreflect.StringKind:
+		return v.String(), nil
+	case protoreflect.BytesKind:
+		return append([]byte(nil), v.Bytes()...), nil
+	case protoreflect.EnumKind:
+		return int32(v.Enum()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageToMap(v.Message())
+	default:
+		return nil, fmt.Errorf("unsupported kind %v for field %s", fd.Kind(), fd.FullName())
+	}
+}
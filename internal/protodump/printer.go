@@ -0,0 +1,134 @@
+// Package protodump implements the text format written by the protodump command-line tool so that the
+// same output can be produced by other packages, such as lazyproto.
+package protodump
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+// Printer writes protodump-formatted lines to an [io.Writer].  Each line is prefixed with two spaces per
+// level of indentation, and the lines that describe a field's value are indented one additional level
+// relative to the "tag: ..." line for that field.
+//
+// Write errors are not returned by the individual methods.  Callers that need them should write to a
+// buffer, such as a [bufio.Writer] or a [strings.Builder], and check for errors when flushing it.
+type Printer struct {
+	w      io.Writer
+	prefix string
+}
+
+// NewPrinter returns a Printer that writes to w at the specified level of indentation.
+func NewPrinter(w io.Writer, indent int) *Printer {
+	return &Printer{
+		w:      w,
+		prefix: strings.Repeat("  ", indent),
+	}
+}
+
+// Nested returns a Printer that writes to the same destination as p, indented one additional level, for
+// writing the fields of a nested message.
+func (p *Printer) Nested() *Printer {
+	return &Printer{
+		w:      p.w,
+		prefix: p.prefix + "  ",
+	}
+}
+
+// WriteOffset writes the byte offset of a field within the input data.
+func (p *Printer) WriteOffset(offset int) {
+	fmt.Fprintf(p.w, "%soffset: 0x%04X\n", p.prefix, offset)
+}
+
+// WriteTagKeyBytes writes the raw bytes of a field's tag key.
+func (p *Printer) WriteTagKeyBytes(b []byte) {
+	fmt.Fprintf(p.w, "%s  tag key bytes: ", p.prefix)
+	p.writeByteList(b)
+}
+
+// WriteValueBytes writes the raw bytes of a field's encoded value.
+func (p *Printer) WriteValueBytes(b []byte) {
+	fmt.Fprintf(p.w, "%s  value bytes: ", p.prefix)
+	p.writeByteList(b)
+}
+
+// WriteTag writes the tag and wire type of a field.
+func (p *Printer) WriteTag(tag int, wt csproto.WireType) {
+	fmt.Fprintf(p.w, "%stag: %d, wire type: %s\n", p.prefix, tag, wt)
+}
+
+// WriteVarint writes the value of a varint field.
+func (p *Printer) WriteVarint(v int64) {
+	fmt.Fprintf(p.w, "%s  varint: %d\n", p.prefix, v)
+}
+
+// WriteFixed32 writes the value of a fixed32 field.
+func (p *Printer) WriteFixed32(v uint32) {
+	fmt.Fprintf(p.w, "%s  fixed32: %d\n", p.prefix, v)
+}
+
+// WriteFixed64 writes the value of a fixed64 field.
+func (p *Printer) WriteFixed64(v uint64) {
+	fmt.Fprintf(p.w, "%s  fixed64: %d\n", p.prefix, v)
+}
+
+// WriteLength writes the length of a length-delimited field.
+func (p *Printer) WriteLength(n int) {
+	fmt.Fprintf(p.w, "%s  length: %d\n", p.prefix, n)
+}
+
+// WriteStringValue writes the contents of a length-delimited field as a string.
+func (p *Printer) WriteStringValue(s string) {
+	fmt.Fprintf(p.w, "%s  string: %s\n", p.prefix, s)
+}
+
+// WriteBytesValue writes the contents of a length-delimited field as a list of bytes.
+func (p *Printer) WriteBytesValue(b []byte) {
+	fmt.Fprintf(p.w, "%s  ", p.prefix)
+	p.writeByteList(b)
+}
+
+// WriteRawValue writes the value lines for a field with wire type wt and raw value v, which is the
+// encoded value for varint and fixed-width fields and the content of length-delimited fields.  Values
+// that cannot be decoded, and values with any other wire type, are written as a list of bytes.
+func (p *Printer) WriteRawValue(wt csproto.WireType, v []byte) {
+	switch wt {
+	case csproto.WireTypeVarint:
+		if n, _, err := csproto.DecodeVarint(v); err == nil {
+			p.WriteVarint(int64(n))
+			return
+		}
+	case csproto.WireTypeFixed32:
+		if n, _, err := csproto.DecodeFixed32(v); err == nil {
+			p.WriteFixed32(n)
+			return
+		}
+	case csproto.WireTypeFixed64:
+		if n, _, err := csproto.DecodeFixed64(v); err == nil {
+			p.WriteFixed64(n)
+			return
+		}
+	case csproto.WireTypeLengthDelimited:
+		p.WriteLength(len(v))
+	default:
+		// fall through and write the raw bytes
+	}
+	p.WriteBytesValue(v)
+}
+
+// writeByteList writes b as a bracketed, comma-separated list of hex bytes followed by a newline.
+func (p *Printer) writeByteList(b []byte) {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, v := range b {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, "0x%02X", v)
+	}
+	sb.WriteString("]\n")
+	_, _ = io.WriteString(p.w, sb.String())
+}
@@ -0,0 +1,103 @@
+package protodump_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/internal/protodump"
+)
+
+func TestPrinter(t *testing.T) {
+	var sb strings.Builder
+	p := protodump.NewPrinter(&sb, 0)
+	p.WriteOffset(0)
+	p.WriteTagKeyBytes([]byte{0x08})
+	p.WriteTag(1, csproto.WireTypeVarint)
+	p.WriteValueBytes([]byte{0x96, 0x01})
+	p.WriteVarint(150)
+	p.WriteTag(2, csproto.WireTypeLengthDelimited)
+	p.WriteLength(4)
+	p.WriteStringValue("test")
+
+	np := p.Nested()
+	np.WriteTag(3, csproto.WireTypeFixed64)
+	np.WriteFixed64(1138)
+
+	expected := strings.Join([]string{
+		"offset: 0x0000",
+		"  tag key bytes: [0x08]",
+		"tag: 1, wire type: varint",
+		"  value bytes: [0x96,0x01]",
+		"  varint: 150",
+		"tag: 2, wire type: length-delimited",
+		"  length: 4",
+		"  string: test",
+		"  tag: 3, wire type: fixed64",
+		"    fixed64: 1138",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, sb.String())
+}
+
+func TestPrinterWriteRawValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		wt       csproto.WireType
+		v        []byte
+		expected string
+	}{
+		{
+			name:     "varint",
+			wt:       csproto.WireTypeVarint,
+			v:        []byte{0x96, 0x01},
+			expected: "    varint: 150\n",
+		},
+		{
+			name:     "negative varint",
+			wt:       csproto.WireTypeVarint,
+			v:        []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01},
+			expected: "    varint: -1\n",
+		},
+		{
+			name:     "fixed32",
+			wt:       csproto.WireTypeFixed32,
+			v:        []byte{0x72, 0x04, 0x00, 0x00},
+			expected: "    fixed32: 1138\n",
+		},
+		{
+			name:     "fixed64",
+			wt:       csproto.WireTypeFixed64,
+			v:        []byte{0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expected: "    fixed64: 1138\n",
+		},
+		{
+			name:     "length-delimited",
+			wt:       csproto.WireTypeLengthDelimited,
+			v:        []byte("hi"),
+			expected: "    length: 2\n    [0x68,0x69]\n",
+		},
+		{
+			name:     "invalid fixed32",
+			wt:       csproto.WireTypeFixed32,
+			v:        []byte{0x01, 0x02},
+			expected: "    [0x01,0x02]\n",
+		},
+		{
+			name:     "group",
+			wt:       csproto.WireTypeStartGroup,
+			v:        []byte{0x08, 0x01, 0x0C},
+			expected: "    [0x08,0x01,0x0C]\n",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var sb strings.Builder
+			protodump.NewPrinter(&sb, 1).WriteRawValue(tc.wt, tc.v)
+			assert.Equal(t, tc.expected, sb.String())
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	protov1 "github.com/golang/protobuf/proto" //nolint: staticcheck // using this deprecated package intentionally as this is a compatibility shim
 	"google.golang.org/protobuf/encoding/protojson"
 	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // JSONMarshaler returns an implementation of the json.Marshaler interface that formats msg to JSON
@@ -134,13 +135,21 @@ func (m *jsonUnmarshaler) UnmarshalJSON(data []byte) error {
 
 	// Google V2 message?
 	if msg, isV2 := m.msg.(protov2.Message); isV2 {
+		// when partial messages are only allowed within repeated fields, unmarshal with AllowPartial
+		// enabled then do our own required field check that skips the elements of repeated fields
+		checkRequired := m.opts.allowPartialForRepeated && !m.opts.allowPartial
 		mo := protojson.UnmarshalOptions{
-			AllowPartial:   m.opts.allowPartial,
+			AllowPartial:   m.opts.allowPartial || checkRequired,
 			DiscardUnknown: m.opts.allowUnknownFields,
 		}
 		if err := mo.Unmarshal(data, msg); err != nil {
 			return fmt.Errorf("unable to unmarshal JSON data: %w", err)
 		}
+		if checkRequired {
+			if err := checkRequiredFieldsExceptRepeated(msg.ProtoReflect()); err != nil {
+				return fmt.Errorf("unable to unmarshal JSON data: %w", err)
+			}
+		}
 		return nil
 	}
 
@@ -216,6 +225,19 @@ func JSONAllowPartialMessages(allow bool) JSONOption {
 	}
 }
 
+// JSONAllowPartialForRepeated returns a JSON option that configures JSON unmarshaling to not return an
+// error if elements of repeated message fields are missing required fields.  Required fields that are
+// not set anywhere else in the message still result in an error.
+//
+// JSONAllowPartialMessages(true) takes precedence over this option.
+//
+// Note: only applies to Google V2 (google.golang.org/protobuf) messages that are using proto2 syntax.
+func JSONAllowPartialForRepeated(allow bool) JSONOption {
+	return func(opts *jsonOptions) {
+		opts.allowPartialForRepeated = allow
+	}
+}
+
 // jsonOptions defines the JSON formatting options
 //
 // These options are a subset of those available by each of the three supported runtimes.  The supported
@@ -240,4 +262,41 @@ type jsonOptions struct {
 	//
 	// Note: only applies to Google V2 (google.golang.org/protobuf) messages that are using proto2 syntax.
 	allowPartial bool
+	// If true, unmarshaled messages with missing required fields will not return an error as long as
+	// the missing fields are within elements of repeated message fields
+	//
+	// Note: only applies to Google V2 (google.golang.org/protobuf) messages that are using proto2 syntax.
+	allowPartialForRepeated bool
+}
+
+// checkRequiredFieldsExceptRepeated returns an error if any required fields in m, or in any of its
+// singular or map-valued nested messages, are not set.  The elements of repeated message fields are
+// not checked.
+func checkRequiredFieldsExceptRepeated(m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Cardinality() == protoreflect.Required && !m.Has(fd) {
+			return fmt.Errorf("required field %s not set", fd.FullName())
+		}
+	}
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			// elements of repeated fields are allowed to be partial
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				err = checkRequiredFieldsExceptRepeated(mv.Message())
+				return err == nil
+			})
+		case fd.Message() != nil:
+			err = checkRequiredFieldsExceptRepeated(v.Message())
+		}
+		return err == nil
+	})
+	return err
 }
@@ -0,0 +1,96 @@
+package csproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONLineEncoder writes Protobuf messages to an output stream as JSON lines, where each message is
+// formatted as a single line of compact JSON followed by a newline.
+//
+// Output is buffered so callers must call Flush() or Close() to ensure that all messages are written
+// to the underlying writer.
+type JSONLineEncoder struct {
+	w    *bufio.Writer
+	opts []JSONOption
+	buf  bytes.Buffer
+}
+
+// NewJSONLineEncoder returns a new JSONLineEncoder that writes to w using the specified options.  The
+// JSONIndent() option is ignored since each message must be written on a single line.
+func NewJSONLineEncoder(w io.Writer, opts ...JSONOption) *JSONLineEncoder {
+	return &JSONLineEncoder{
+		w:    bufio.NewWriter(w),
+		opts: opts,
+	}
+}
+
+// Encode formats msg as compact JSON and writes it to the stream followed by a newline.  msg can be a
+// Google V1, Google V2, or Gogo message.
+func (e *JSONLineEncoder) Encode(msg interface{}) error {
+	data, err := JSONMarshaler(msg, e.opts...).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("cannot encode a nil message")
+	}
+	e.buf.Reset()
+	if err := json.Compact(&e.buf, data); err != nil {
+		return fmt.Errorf("unable to compact JSON for message: %w", err)
+	}
+	_ = e.buf.WriteByte('\n')
+	if _, err := e.w.Write(e.buf.Bytes()); err != nil {
+		return fmt.Errorf("unable to write JSON line: %w", err)
+	}
+	return nil
+}
+
+// Flush writes any buffered output to the underlying writer.
+func (e *JSONLineEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+// Close flushes any buffered output to the underlying writer.  The underlying writer is not closed.
+func (e *JSONLineEncoder) Close() error {
+	return e.Flush()
+}
+
+// JSONLineDecoder reads Protobuf messages from an input stream of JSON lines, where each line contains
+// a single JSON-formatted message.  Empty lines are ignored.
+type JSONLineDecoder struct {
+	r    *bufio.Reader
+	opts []JSONOption
+}
+
+// NewJSONLineDecoder returns a new JSONLineDecoder that reads from r using the specified options.
+func NewJSONLineDecoder(r io.Reader, opts ...JSONOption) *JSONLineDecoder {
+	return &JSONLineDecoder{
+		r:    bufio.NewReader(r),
+		opts: opts,
+	}
+}
+
+// Decode reads the next line from the stream and unmarshals it into msg, which can be a Google V1,
+// Google V2, or Gogo message.
+//
+// io.EOF is returned when there are no more messages in the stream.
+func (d *JSONLineDecoder) Decode(msg interface{}) error {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return io.EOF
+			}
+			continue
+		}
+		return JSONUnmarshaler(msg, d.opts...).UnmarshalJSON(line)
+	}
+}
@@ -0,0 +1,80 @@
+package lazyproto
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkDecodeMany(b *testing.B) {
+	msg := []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: varint 1 }
+		(3 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1)
+	for _, n := range []int{10, 100, 1000} {
+		datas := make([][]byte, n)
+		for i := range datas {
+			datas[i] = msg
+		}
+		b.Run(fmt.Sprintf("DecodeMany/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, release, err := DecodeMany(datas, def)
+				if err != nil {
+					b.Fatalf("error decoding: %v", err)
+				}
+				release()
+			}
+		})
+		b.Run(fmt.Sprintf("Decode/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, data := range datas {
+					res, err := Decode(data, def)
+					if err != nil {
+						b.Fatalf("error decoding: %v", err)
+					}
+					_ = res.Close()
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeWithFilterFunc(b *testing.B) {
+	msg := []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: varint 1 }
+		(3 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1)
+	cases := []struct {
+		name string
+		opts []DecoderOption
+	}{
+		{name: "no filter"},
+		{name: "include all", opts: []DecoderOption{WithFilterFunc(func(int) bool { return true })}},
+		{name: "exclude one", opts: []DecoderOption{WithFilterFunc(func(tag int) bool { return tag != 2 })}},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				res, err := Decode(msg, def, tc.opts...)
+				if err != nil {
+					b.Fatalf("error decoding: %v", err)
+				}
+				_ = res.Close()
+			}
+		})
+	}
+}
@@ -1,15 +1,33 @@
 package lazyproto
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/internal/protodump"
 )
 
 var (
 	// ErrTagNotFound is returned by [PartialDecodeResult.FieldData] when the specified tag(s) do not
 	// exist in the result.
 	ErrTagNotFound = fmt.Errorf("the requested tag does not exist in the partial decode result")
+	// ErrDefMismatch is returned by [DecodeResult.Diff] when the two results were decoded using
+	// different definitions.
+	ErrDefMismatch = fmt.Errorf("the decode results were produced using different definitions")
+	// ErrRecursionLimitExceeded is returned by [Decode] when the data contains nested messages that are
+	// deeper than the limit configured using [WithMaxDepth].
+	ErrRecursionLimitExceeded = fmt.Errorf("nested messages exceed the maximum depth")
+	// ErrNestedMessageTooLarge is returned by [Decode] when the data contains a nested message that is
+	// larger than the limit configured using [WithMaxNestedMessageSize].
+	ErrNestedMessageTooLarge = fmt.Errorf("nested message exceeds the maximum size")
 )
 
 var emptyResult DecodeResult
@@ -26,24 +44,140 @@ var emptyResult DecodeResult
 // of field values are needed, so [PartialDecodeResult] and [FieldData] only support extracting
 // scalar values or slices of scalar values. Consumers that need to decode entire messages will need
 // to use [Unmarshal] instead.
-func Decode(data []byte, def Def) (res DecodeResult, err error) {
-	if len(data) == 0 || len(def) == 0 {
-		return emptyResult, nil
+//
+// The behavior of the decoder can be customized by passing one or more [DecoderOption] values.
+//
+// If the data cannot be decoded, the returned error will be a [*TagPathError] that identifies the
+// (possibly nested) field that caused the failure whenever the field is known.  Use [WithErrorHandler]
+// to skip invalid fields rather than failing on the first error.
+func Decode(data []byte, def Def, opts ...DecoderOption) (res DecodeResult, err error) {
+	o := newDecodeOptions(opts)
+	if !o.shouldDecode(data, def) {
+		return DecodeResult{def: def, closeHooks: o.closeHooks}, nil
 	}
 	if err := def.Validate(); err != nil {
 		return emptyResult, err
 	}
-	res.m = fieldDataMapPool.Get().(map[int]*FieldData)
-	defer func() {
-		// call res.Close() on error to clean up field data
-		if err != nil {
-			_ = res.Close()
+	return decode(data, def, nil, o)
+}
+
+// DecodeContext is equivalent to [Decode] but checks ctx at each field boundary, including the fields of
+// nested messages, and stops decoding if ctx is cancelled.  This bounds the amount of time spent
+// decoding large messages for services with request deadlines.
+//
+// If ctx is cancelled before decoding completes, the returned error is ctx.Err().
+func DecodeContext(ctx context.Context, data []byte, def Def, opts ...DecoderOption) (DecodeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return emptyResult, err
+	}
+	o := newDecodeOptions(opts)
+	if !o.shouldDecode(data, def) {
+		return DecodeResult{def: def, closeHooks: o.closeHooks}, nil
+	}
+	if err := def.Validate(); err != nil {
+		return emptyResult, err
+	}
+	o.ctx = ctx
+	res, err := decode(data, def, nil, o)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return emptyResult, ctxErr
+		}
+		return emptyResult, err
+	}
+	return res, nil
+}
+
+// DecodeMany extracts the fields in def from each of the messages in datas, which is more efficient
+// than calling [Decode] in a loop since def is only validated once.
+//
+// The returned slice contains one result for each message, in the same order as datas, and the returned
+// function closes all of the results in a single call.  Consumers should always call that function
+// once they are done with the results.
+//
+// If any message cannot be decoded, all of the results decoded so far are closed and the returned error
+// includes the index of the message that failed.
+func DecodeMany(datas [][]byte, def Def, opts ...DecoderOption) ([]*DecodeResult, func(), error) {
+	if err := def.Validate(); err != nil {
+		return nil, func() {}, err
+	}
+	o := newDecodeOptions(opts)
+	results := make([]*DecodeResult, len(datas))
+	release := func() {
+		for _, r := range results {
+			if r != nil {
+				_ = r.Close()
+			}
+		}
+	}
+	for i, data := range datas {
+		res := DecodeResult{def: def, closeHooks: o.closeHooks}
+		if o.shouldDecode(data, def) {
+			var err error
+			if res, err = decode(data, def, nil, o); err != nil {
+				release()
+				return nil, func() {}, fmt.Errorf("unable to decode message at index %d: %w", i, err)
+			}
 		}
-	}()
+		results[i] = &res
+	}
+	return results, release, nil
+}
+
+// decode is the internal implementation of [Decode], which assumes that def has already been validated.
+//
+// The path parameter is the tag "path" leading to the current message if it is nested, and is used to
+// construct a [TagPathError] for any errors that occur.
+func decode(data []byte, def Def, path []int, opts *decodeOptions) (DecodeResult, error) {
+	if !opts.shouldDecode(data, def) {
+		return DecodeResult{def: def}, nil
+	}
+	start := opts.startObserving(path)
+	res := DecodeResult{
+		def: def,
+		m:   fieldDataMapPool.Get().(map[int]*FieldData),
+	}
+	if err := decodeFields(&res, data, def, path, opts); err != nil {
+		// clean up field data on error
+		_ = res.Close()
+		return emptyResult, err
+	}
+	if len(path) == 0 {
+		res.closeHooks = opts.closeHooks
+		opts.observe(start, len(data))
+	}
+	return res, nil
+}
+
+// decodeFields decodes the fields in def from data and adds them to res.
+func decodeFields(res *DecodeResult, data []byte, def Def, path []int, opts *decodeOptions) error {
+	// fieldErr reports an error for the specified field to the configured error handler, if any, and
+	// returns nil if the handler indicated that decoding should continue
+	fieldErr := func(tag int, wt csproto.WireType, err error) error {
+		err = newTagPathError(path, tag, err)
+		if opts.errorHandler != nil && opts.errorHandler(tag, wt, err) {
+			return nil
+		}
+		return err
+	}
 	for dec := csproto.NewDecoder(data); dec.More(); {
+		if opts.ctx != nil {
+			select {
+			case <-opts.ctx.Done():
+				return opts.ctx.Err()
+			default:
+			}
+		}
 		tag, wt, err := dec.DecodeTag()
 		if err != nil {
-			return emptyResult, err
+			if len(path) > 0 {
+				err = &TagPathError{Path: path, Err: err}
+			}
+			if opts.errorHandler != nil && opts.errorHandler(tag, wt, err) {
+				// the rest of the data cannot be parsed without a valid tag so stop here
+				return nil
+			}
+			return err
 		}
 		var (
 			dv            Def
@@ -51,17 +185,36 @@ func Decode(data []byte, def Def) (res DecodeResult, err error) {
 		)
 		dv, want = def.Get(tag)
 		_, wantRaw = def.Get(-1 * tag)
+		if (want || wantRaw) && len(path) == 0 && opts.filter != nil && !opts.filter(tag) {
+			want, wantRaw = false, false
+		}
+		if (want || wantRaw) && len(path) == 0 && opts.limitReached(tag, res.capturedCount(tag, wantRaw)) {
+			// skip the field without treating it as unknown
+			if _, err := dec.Skip(tag, wt); err != nil {
+				if err := fieldErr(tag, wt, err); err != nil {
+					return err
+				}
+				// the end of the field is unknown so stop here
+				return nil
+			}
+			continue
+		}
 		if !want && !wantRaw {
+			valueStart := dec.Offset()
 			if _, err := dec.Skip(tag, wt); err != nil {
-				return emptyResult, err
+				if err := fieldErr(tag, wt, err); err != nil {
+					return err
+				}
+				// the end of the field is unknown so stop here
+				return nil
+			}
+			if opts.keepUnknown && len(path) == 0 {
+				res.addUnknownField(tag, wt, data[valueStart:dec.Offset()])
 			}
 			continue
 		}
 		switch wt {
 		case csproto.WireTypeVarint, csproto.WireTypeFixed32, csproto.WireTypeFixed64:
-			if wantRaw {
-				return emptyResult, fmt.Errorf("invalid definition: raw mode only supported for length-delimited fields (tag=%d, wire type=%s)", tag, wt)
-			}
 			// varint, fixed32, and fixed64 could be multiple Go types so
 			// grab the raw bytes and defer interpreting them to the consumer/caller
 			// . varint -> int32, int64, uint32, uint64, sint32, sint64, bool, enum
@@ -69,11 +222,24 @@ func Decode(data []byte, def Def) (res DecodeResult, err error) {
 			// . fixed64 -> int32, uint64, float64
 			val, err := dec.Skip(tag, wt)
 			if err != nil {
-				return emptyResult, err
+				if err := fieldErr(tag, wt, err); err != nil {
+					return err
+				}
+				// the end of the field is unknown so stop here
+				return nil
+			}
+			if wantRaw {
+				if err := fieldErr(tag, wt, fmt.Errorf("invalid definition: raw mode only supported for length-delimited fields (tag=%d, wire type=%s)", tag, wt)); err != nil {
+					return err
+				}
+				continue
 			}
 			fd, err := res.getOrAddFieldData(tag, wt)
 			if err != nil {
-				return emptyResult, err
+				if err := fieldErr(tag, wt, err); err != nil {
+					return err
+				}
+				continue
 			}
 			// Skip() returns the entire field contents, both the tag and the value, so we need to skip past the tag
 			val = val[csproto.SizeOfTagKey(tag):]
@@ -81,64 +247,417 @@ func Decode(data []byte, def Def) (res DecodeResult, err error) {
 		case csproto.WireTypeLengthDelimited:
 			val, err := dec.DecodeBytes()
 			if err != nil {
-				return emptyResult, err
+				if err := fieldErr(tag, wt, err); err != nil {
+					return err
+				}
+				// the end of the field is unknown so stop here
+				return nil
 			}
 			if len(dv) > 0 {
+				if opts.maxDepth > 0 && len(path) >= opts.maxDepth {
+					if err := fieldErr(tag, wt, fmt.Errorf("%w (%d)", ErrRecursionLimitExceeded, opts.maxDepth)); err != nil {
+						return err
+					}
+					continue
+				}
+				if opts.maxNestedSize > 0 && len(val) > opts.maxNestedSize {
+					if err := fieldErr(tag, wt, fmt.Errorf("%w (%d > %d)", ErrNestedMessageTooLarge, len(val), opts.maxNestedSize)); err != nil {
+						return err
+					}
+					continue
+				}
 				// recurse
-				subResult, err := Decode(val, dv)
+				// . errors in the nested message have already been passed to the error handler, if any
+				subResult, err := decode(val, dv, append(path[:len(path):len(path)], tag), opts)
 				if err != nil {
-					return emptyResult, err
+					return newTagPathError(path, tag, err)
 				}
 				fd, err := res.getOrAddFieldData(tag, wt)
 				if err != nil {
-					return emptyResult, err
+					_ = subResult.Close()
+					if err := fieldErr(tag, wt, err); err != nil {
+						return err
+					}
+					continue
 				}
 				fd.data = append(fd.data, subResult.m)
 			} else {
 				fd, err := res.getOrAddFieldData(tag, wt)
 				if err != nil {
-					return emptyResult, err
+					if err := fieldErr(tag, wt, err); err != nil {
+						return err
+					}
+					continue
 				}
 				fd.data = append(fd.data, val)
 			}
 			if wantRaw {
 				fd, err := res.getOrAddFieldData(-1*tag, wt)
 				if err != nil {
-					return emptyResult, err
+					if err := fieldErr(tag, wt, err); err != nil {
+						return err
+					}
+					continue
 				}
 				fd.data = append(fd.data, val)
 			}
 		default:
-			return emptyResult, fmt.Errorf("read unknown/unsupported protobuf wire type (%v)", wt)
+			if err := fieldErr(tag, wt, fmt.Errorf("read unknown/unsupported protobuf wire type (%v)", wt)); err != nil {
+				return err
+			}
+			if _, err := dec.Skip(tag, wt); err != nil {
+				// the end of the field is unknown so stop here
+				return nil
+			}
 		}
 	}
-	return res, nil
+	return nil
 }
 
 // DecodeResult holds a (possibly nested) mapping of integer field tags to FieldData instances
 // which can be used to retrieve typed values for specific Protobuf message fields.
 type DecodeResult struct {
 	m map[int]*FieldData
+	// the definition used to produce the result, if it was returned by Decode() or DecodeMany()
+	def Def
+	// the raw values and wire types of the top-level fields that were not in the definition, if
+	// WithUnknownFields() was used
+	unknown          map[int][][]byte
+	unknownWireTypes map[int]csproto.WireType
+	// true if r was returned by Copy() and does not hold any pooled resources
+	detached bool
+	// true if r was returned by NestedResultsAll() and refers to field data owned by another result
+	borrowed bool
+	// functions to call when r is closed, registered using WithCloseHook()
+	closeHooks []func(*DecodeResult)
 }
 
 // Close releases all internal resources held by r.
 //
 // Consumers should always call Close() on instances returned by [Decode] to ensure that internal
-// resources are cleaned up.
+// resources are cleaned up.  Any hooks registered using [WithCloseHook] are called once the field data
+// has been cleared, before the internal resources are released.
 func (r *DecodeResult) Close() error {
+	if r.detached || r.borrowed {
+		return nil
+	}
 	for k, v := range r.m {
 		if v != nil {
 			v.close()
 		}
 		delete(r.m, k)
 	}
+	r.unknown, r.unknownWireTypes = nil, nil
+	hooks := r.closeHooks
+	r.closeHooks = nil
+	for _, fn := range hooks {
+		fn(r)
+	}
 	if r.m != nil {
 		fieldDataMapPool.Put(r.m)
 	}
 	r.m = nil
+	r.def = nil
+	return nil
+}
+
+// DecodeInto replaces the field data in r with the fields decoded from data, using the same [Def] that
+// was passed to [Decode], which avoids the overhead of allocating a new result for each message when
+// decoding in a loop.  Decoder options used for the original decode are not retained, so any options
+// must be passed again, except for hooks registered using [WithCloseHook], which are only replaced if
+// opts includes new hooks.
+//
+// The existing top-level field data entries are cleared and re-used rather than being released, so r
+// must not be shared between goroutines and any [FieldData] or values previously retrieved from r are
+// invalid once DecodeInto is called.  Consumers must still call Close() once they are done with r.
+//
+// If the data cannot be decoded, r is left empty and the error is returned.
+func (r *DecodeResult) DecodeInto(data []byte, opts ...DecoderOption) error {
+	if r == nil {
+		return fmt.Errorf("cannot decode into a nil result")
+	}
+	if r.borrowed {
+		return fmt.Errorf("cannot decode into a result returned by NestedResultsAll()")
+	}
+	r.reset()
+	o := newDecodeOptions(opts)
+	if len(o.closeHooks) > 0 {
+		r.closeHooks = o.closeHooks
+	}
+	if !o.shouldDecode(data, r.def) {
+		return nil
+	}
+	start := o.startObserving(nil)
+	if err := decodeFields(r, data, r.def, nil, o); err != nil {
+		r.reset()
+		return err
+	}
+	o.observe(start, len(data))
 	return nil
 }
 
+// reset clears the values held by r while keeping the top-level field data entries for re-use.
+func (r *DecodeResult) reset() {
+	for _, fd := range r.m {
+		if fd == nil {
+			continue
+		}
+		data := fd.data
+		fd.close()
+		fd.data = data[:0]
+	}
+	r.unknown, r.unknownWireTypes = nil, nil
+}
+
+// Copy returns a deep copy of r that does not share any memory with r or with the data passed to
+// [Decode] and does not use any pooled resources, so it remains valid after r is closed and can be
+// retained beyond the scope of the current request.  Calling Close() on the copy is a no-op.
+//
+// Copy returns an empty result if r is nil.
+func (r *DecodeResult) Copy() *DecodeResult {
+	res := &DecodeResult{detached: true}
+	if r == nil {
+		return res
+	}
+	res.def = r.def
+	res.m = copyFieldDataMap(r.m)
+	if r.unknown != nil {
+		res.unknown = make(map[int][][]byte, len(r.unknown))
+		res.unknownWireTypes = make(map[int]csproto.WireType, len(r.unknownWireTypes))
+		for tag, vals := range r.unknown {
+			res.unknown[tag] = copyByteSlices(vals)
+			res.unknownWireTypes[tag] = r.unknownWireTypes[tag]
+		}
+	}
+	return res
+}
+
+// copyFieldDataMap returns a deep copy of m that is not allocated from the pool
+func copyFieldDataMap(m map[int]*FieldData) map[int]*FieldData {
+	if m == nil {
+		return nil
+	}
+	res := make(map[int]*FieldData, len(m))
+	for tag, fd := range m {
+		if fd == nil {
+			res[tag] = nil
+			continue
+		}
+		cp := &FieldData{wt: fd.wt, data: make([]any, len(fd.data))}
+		for i, d := range fd.data {
+			switch v := d.(type) {
+			case []byte:
+				cp.data[i] = append([]byte{}, v...)
+			case map[int]*FieldData:
+				cp.data[i] = copyFieldDataMap(v)
+			default:
+				cp.data[i] = v
+			}
+		}
+		res[tag] = cp
+	}
+	return res
+}
+
+// copyByteSlices returns a deep copy of vals
+func copyByteSlices(vals [][]byte) [][]byte {
+	res := make([][]byte, len(vals))
+	for i, v := range vals {
+		res[i] = append([]byte{}, v...)
+	}
+	return res
+}
+
+// String returns a human-readable representation of the decoded fields in r, with one line per tag
+// in ascending order:
+//
+//	tag 1 [varint]: 0x96
+//	tag 2 [length-delimited]: "testing"
+//	tag 3 [fixed32]: 0x00000472, 0x00000001
+//
+// Varint and fixed-width values are formatted as hex and length-delimited values are formatted as quoted
+// strings.  Nested messages are written inline inside of braces.
+func (r *DecodeResult) String() string {
+	if r == nil {
+		return "<nil>"
+	}
+	var sb strings.Builder
+	stringLayout.writeFieldDataMap(&sb, r.m, 0)
+	return sb.String()
+}
+
+// DebugString returns a diagnostic dump of the decoded fields in r, with one line per tag in ascending
+// order that includes the wire type, the number of values, and the length and a hex preview of the raw
+// bytes of each value.  Nested messages are written inside of braces with their fields indented:
+//
+//	tag 1 [varint] count=2: len=2 [96 01], len=1 [01]
+//	tag 2 [length-delimited] count=1: len=7 [74 65 73 74 69 6e 67]
+//	tag 3 [length-delimited] count=1: {
+//	  tag 1 [fixed32] count=1: len=4 [72 04 00 00]
+//	}
+//
+// The output is intended for debugging and its format may change between releases.
+func (r *DecodeResult) DebugString() string {
+	if r == nil {
+		return "<nil>"
+	}
+	var sb strings.Builder
+	debugLayout.writeFieldDataMap(&sb, r.m, 0)
+	return sb.String()
+}
+
+// Dump returns an [io.WriterTo] that writes a text representation of the decoded fields in r in the same
+// format as the protodump command-line tool, which is useful for inspecting results in tests or while
+// debugging.  Fields are written in ascending tag order, and nested messages that were decoded using a
+// nested [Def] are always expanded.  Length-delimited values are written as a list of bytes since the
+// encoded data does not indicate whether or not they are strings.
+//
+// The returned value references r, so it must be used before r is closed.
+func (r *DecodeResult) Dump() io.WriterTo {
+	var m map[int]*FieldData
+	if r != nil {
+		m = r.m
+	}
+	return resultDumper{m: m}
+}
+
+// resultDumper implements [io.WriterTo] for [DecodeResult.Dump].
+type resultDumper struct {
+	m map[int]*FieldData
+}
+
+// WriteTo writes the protodump-style representation of the field data to w.
+func (d resultDumper) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	writeDumpFieldDataMap(protodump.NewPrinter(&sb, 0), d.m)
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// writeDumpFieldDataMap writes the entries in m to p for Dump().
+func writeDumpFieldDataMap(p *protodump.Printer, m map[int]*FieldData) {
+	for _, tag := range sortedTags(m) {
+		if tag < 0 {
+			// raw field data is the same as the data for the positive tag
+			continue
+		}
+		fd := m[tag]
+		for _, d := range fd.data {
+			p.WriteTag(tag, fd.wt)
+			switch tv := d.(type) {
+			case map[int]*FieldData:
+				p.WriteLength(len(appendFieldDataMap(nil, tv)))
+				writeDumpFieldDataMap(p.Nested(), tv)
+			case []byte:
+				p.WriteRawValue(fd.wt, tv)
+			}
+		}
+	}
+}
+
+// fieldDataLayout describes the text format used by String(), DebugString() and FieldData.String().
+type fieldDataLayout struct {
+	// indent is repeated once per level of nesting at the start of each field of a nested message.  If
+	// indent is empty, nested messages are written on a single line with their fields separated by "; ".
+	indent string
+	// counts, if true, includes the number of values of each field after its wire type
+	counts bool
+	// writeValue writes a single raw value with the specified wire type
+	writeValue func(sb *strings.Builder, wt csproto.WireType, v []byte)
+}
+
+var (
+	// stringLayout is the format used by DecodeResult.String()
+	stringLayout = fieldDataLayout{writeValue: writeRawValue}
+	// debugLayout is the format used by DecodeResult.DebugString()
+	debugLayout = fieldDataLayout{indent: "  ", counts: true, writeValue: writeDebugRawValue}
+)
+
+// writeFieldDataMap writes the entries in m, which are nested depth levels deep, to sb in ascending tag
+// order.
+func (l fieldDataLayout) writeFieldDataMap(sb *strings.Builder, m map[int]*FieldData, depth int) {
+	sep := "\n"
+	if depth > 0 && l.indent == "" {
+		sep = "; "
+	}
+	prefix := strings.Repeat(l.indent, depth)
+	for i, tag := range sortedTags(m) {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		fd := m[tag]
+		fmt.Fprintf(sb, "%stag %d [%s]", prefix, tag, fd.wt)
+		if l.counts {
+			fmt.Fprintf(sb, " count=%d", len(fd.data))
+		}
+		sb.WriteString(": ")
+		l.writeFieldValues(sb, fd, depth)
+	}
+}
+
+// writeFieldValues writes the values of fd, which is a field of a message nested depth levels deep, to
+// sb separated by commas.
+func (l fieldDataLayout) writeFieldValues(sb *strings.Builder, fd *FieldData, depth int) {
+	for i, d := range fd.data {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		switch tv := d.(type) {
+		case map[int]*FieldData:
+			if l.indent == "" || len(sortedTags(tv)) == 0 {
+				sb.WriteString("{")
+				l.writeFieldDataMap(sb, tv, depth+1)
+				sb.WriteString("}")
+				continue
+			}
+			sb.WriteString("{\n")
+			l.writeFieldDataMap(sb, tv, depth+1)
+			fmt.Fprintf(sb, "\n%s}", strings.Repeat(l.indent, depth))
+		case []byte:
+			l.writeValue(sb, fd.wt, tv)
+		default:
+			fmt.Fprintf(sb, "%v", tv)
+		}
+	}
+}
+
+// writeDebugRawValue writes the length of v and up to the first fieldDataStringMaxBytes bytes in hex.
+func writeDebugRawValue(sb *strings.Builder, _ csproto.WireType, v []byte) {
+	if len(v) > fieldDataStringMaxBytes {
+		fmt.Fprintf(sb, "len=%d [% x ...]", len(v), v[:fieldDataStringMaxBytes])
+	} else {
+		fmt.Fprintf(sb, "len=%d [% x]", len(v), v)
+	}
+}
+
+// writeRawValue writes v to sb as hex for varint and fixed-width wire types and as a quoted string
+// for length-delimited values.
+func writeRawValue(sb *strings.Builder, wt csproto.WireType, v []byte) {
+	switch wt {
+	case csproto.WireTypeVarint:
+		if n, _, err := csproto.DecodeVarint(v); err == nil {
+			fmt.Fprintf(sb, "%#x", n)
+			return
+		}
+	case csproto.WireTypeFixed32:
+		if n, _, err := csproto.DecodeFixed32(v); err == nil {
+			fmt.Fprintf(sb, "0x%08x", n)
+			return
+		}
+	case csproto.WireTypeFixed64:
+		if n, _, err := csproto.DecodeFixed64(v); err == nil {
+			fmt.Fprintf(sb, "0x%016x", n)
+			return
+		}
+	case csproto.WireTypeLengthDelimited:
+		fmt.Fprintf(sb, "%q", v)
+		return
+	default:
+		// fall through and write the raw bytes
+	}
+	fmt.Fprintf(sb, "% x", v)
+}
+
 // The FieldData method returns a FieldData instance for the specified tag "path", if it exists.
 //
 // The tags parameter is a list of one or more integer field tags that act as a "path" to a particular
@@ -149,18 +668,8 @@ func (r *DecodeResult) FieldData(tags ...int) (*FieldData, error) {
 	if r == nil || len(r.m) == 0 {
 		return nil, ErrTagNotFound
 	}
-	if len(tags) == 0 {
-		return nil, fmt.Errorf("at least one tag key must be specified")
-	}
-	// special case:
-	// - negative tag values are used to extract the raw bytes of a field, but it must be the only
-	//   (or last) field in the path
-	if len(tags) > 1 {
-		for i := 0; i < len(tags)-1; i++ {
-			if tags[i] < 0 {
-				return nil, fmt.Errorf("invalid tag in path at index %d, negative tags must be the last (or only) path item", i)
-			}
-		}
+	if err := checkTagPath(tags); err != nil {
+		return nil, err
 	}
 	var (
 		fd *FieldData
@@ -180,11 +689,799 @@ func (r *DecodeResult) FieldData(tags ...int) (*FieldData, error) {
 	return nil, ErrTagNotFound
 }
 
+// HasTag returns true if the decoded data contains at least one value for the specified top-level
+// tag.  Unlike FieldData(), HasTag() does not return an error and is safe to call on a nil or closed
+// result.
+func (r *DecodeResult) HasTag(tag int) bool {
+	if r == nil {
+		return false
+	}
+	return r.m[tag].Has()
+}
+
+// FieldCount returns the number of top-level tags in r that have at least one value, including values
+// that are the zero value for their type.  Raw field data requested using negative tags in the [Def] is
+// counted separately.
+func (r *DecodeResult) FieldCount() int {
+	if r == nil {
+		return 0
+	}
+	n := 0
+	for _, fd := range r.m {
+		if fd.Has() {
+			n++
+		}
+	}
+	return n
+}
+
+// Tags returns the top-level tags in r that have at least one value, in ascending order, which allows
+// callers to enumerate the decoded fields without knowing the complete set ahead of time.  As with
+// FieldCount(), raw field data requested using negative tags in the [Def] is included separately as the
+// negative tag, so len(r.Tags()) is always equal to r.FieldCount().
+//
+// Tags returns nil for a nil, empty, or closed result.
+func (r *DecodeResult) Tags() []int {
+	if r == nil {
+		return nil
+	}
+	return sortedTags(r.m)
+}
+
+// AllTags returns the top-level tags in the [Def] that was used to produce r in ascending order,
+// regardless of whether or not they were present in the decoded data.  Use Tags() to retrieve only the
+// tags that have values.  Negative tags for raw field data are included as they appear in the [Def].
+//
+// AllTags returns nil for a nil or closed result, or a result that was not returned by [Decode],
+// [DecodeContext], or [DecodeMany].
+func (r *DecodeResult) AllTags() []int {
+	if r == nil || len(r.def) == 0 {
+		return nil
+	}
+	tags := make([]int, 0, len(r.def))
+	for tag := range r.def {
+		tags = append(tags, tag)
+	}
+	sort.Ints(tags)
+	return tags
+}
+
+// sortedTags returns the tags in m that have at least one value in ascending order
+func sortedTags(m map[int]*FieldData) []int {
+	var tags []int
+	for tag, fd := range m {
+		if fd.Has() {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Ints(tags)
+	return tags
+}
+
+// Range calls fn for each top-level tag in r that has at least one value, in the same ascending order
+// as Tags(), passing the tag and its field data.  Iteration stops if fn returns false.
+//
+// The [FieldData] passed to fn is owned by r and must not be used after r is closed.
+func (r *DecodeResult) Range(fn func(tag int, fd *FieldData) bool) {
+	for _, tag := range r.Tags() {
+		if !fn(tag, r.m[tag]) {
+			return
+		}
+	}
+}
+
+// UnknownFields returns the raw values of the top-level fields that were present in the data but were not
+// decoded because they are not in the [Def], or were excluded by [WithFilterFunc], keyed by tag, in the
+// order that they appeared in the data.  Like [FieldData.RawBytes], each value is the encoded value
+// without the tag key or, for length-delimited fields, the length prefix, so a field can be forwarded
+// verbatim by passing the tag, the wire type returned by UnknownFieldWireType(), and each value to
+// [csproto.Encoder.EncodeRawField].  The returned slices refer to the data passed to [Decode].
+//
+// Unknown fields are only retained when the [WithUnknownFields] option is used.  Otherwise, UnknownFields
+// returns nil.
+func (r *DecodeResult) UnknownFields() map[int][][]byte {
+	if r == nil {
+		return nil
+	}
+	return r.unknown
+}
+
+// UnknownFieldWireType returns the Protobuf wire type of the unknown field with the specified tag.  If
+// the tag is not in the map returned by UnknownFields(), UnknownFieldWireType returns -1 and
+// [ErrTagNotFound].
+func (r *DecodeResult) UnknownFieldWireType(tag int) (csproto.WireType, error) {
+	if r == nil {
+		return -1, ErrTagNotFound
+	}
+	wt, ok := r.unknownWireTypes[tag]
+	if !ok {
+		return -1, ErrTagNotFound
+	}
+	return wt, nil
+}
+
+// addUnknownField records the raw value of an unknown top-level field, where raw is the encoded value
+// that follows the tag key.
+func (r *DecodeResult) addUnknownField(tag int, wt csproto.WireType, raw []byte) {
+	if wt == csproto.WireTypeLengthDelimited {
+		// the data was already validated by Skip() so the length prefix is well-formed
+		_, n, _ := csproto.DecodeVarint(raw)
+		raw = raw[n:]
+	}
+	if r.unknown == nil {
+		r.unknown = make(map[int][][]byte)
+		r.unknownWireTypes = make(map[int]csproto.WireType)
+	}
+	r.unknown[tag] = append(r.unknown[tag], raw)
+	r.unknownWireTypes[tag] = wt
+}
+
+// MarshalProto re-encodes the fields captured in r, including nested messages, as binary Protobuf data
+// that can be decoded by [csproto.NewDecoder] or unmarshaled into the corresponding message type.  This
+// supports projecting a subset of a message's fields without a full unmarshal and re-marshal.
+//
+// Fields are written in ascending tag order and repeated values are written in the order that they were
+// decoded.  Raw field data requested using negative tags is not written separately since it is the same
+// as the data for the corresponding positive tag, and fields returned by UnknownFields() are not
+// included.
+//
+// MarshalProto returns an empty slice for a nil, empty, or closed result.
+func (r *DecodeResult) MarshalProto() ([]byte, error) {
+	if r == nil {
+		return []byte{}, nil
+	}
+	return appendFieldDataMap([]byte{}, r.m), nil
+}
+
+// Equal returns true if r and other contain the same set of tags and the same raw field data for each
+// tag, including the order of the values for repeated fields.  Nested messages are compared
+// recursively.  Values are compared byte-for-byte, without interpreting them as any particular type.
+//
+// A nil result is equal to an empty one.
+func (r *DecodeResult) Equal(other *DecodeResult) bool {
+	var m1, m2 map[int]*FieldData
+	if r != nil {
+		m1 = r.m
+	}
+	if other != nil {
+		m2 = other.m
+	}
+	return fieldDataMapsEqual(m1, m2)
+}
+
+// fieldDataMapsEqual returns true if m1 and m2 contain the same tags and equal field data for each
+// tag.  Entries without any values are ignored.
+func fieldDataMapsEqual(m1, m2 map[int]*FieldData) bool {
+	n1, n2 := 0, 0
+	for tag, fd1 := range m1 {
+		if !fd1.Has() {
+			continue
+		}
+		n1++
+		fd2 := m2[tag]
+		if !fd2.Has() || fd1.wt != fd2.wt || len(fd1.data) != len(fd2.data) {
+			return false
+		}
+		for i := range fd1.data {
+			switch v1 := fd1.data[i].(type) {
+			case []byte:
+				v2, ok := fd2.data[i].([]byte)
+				if !ok || !bytes.Equal(v1, v2) {
+					return false
+				}
+			case map[int]*FieldData:
+				v2, ok := fd2.data[i].(map[int]*FieldData)
+				if !ok || !fieldDataMapsEqual(v1, v2) {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+	}
+	for _, fd2 := range m2 {
+		if fd2.Has() {
+			n2++
+		}
+	}
+	return n1 == n2
+}
+
+// TagDiff describes a top-level tag whose field data differs between two decode results.
+type TagDiff struct {
+	// Tag is the field tag
+	Tag int
+	// LeftData holds the raw data for each value of the tag in the result Diff() was called on, or is
+	// empty if the tag is not present in that result
+	LeftData [][]byte
+	// RightData holds the raw data for each value of the tag in the other result, or is empty if the tag
+	// is not present in that result
+	RightData [][]byte
+}
+
+// Diff compares r with other and returns a TagDiff for each top-level tag whose field data differs,
+// in ascending tag order.  A nil or empty slice is returned if the results are equal.
+//
+// As with Equal(), values are compared byte-for-byte.  The data for nested messages is the encoding of
+// the fields that were decoded from that message, in ascending tag order, rather than the original bytes
+// of the nested message.
+//
+// Both results must have been produced by [Decode] or [DecodeMany] using the same [Def], otherwise
+// [ErrDefMismatch] is returned.  A nil result is treated as an empty result.  The mismatch is reported as
+// an error, rather than as differences, because results decoded with different definitions hold
+// different sets of tags, and in different forms for nested messages and raw fields, so a per-tag diff
+// would report differences in the definitions rather than in the data.
+func (r *DecodeResult) Diff(other *DecodeResult) ([]TagDiff, error) {
+	var left, right DecodeResult
+	if r != nil {
+		left = *r
+	}
+	if other != nil {
+		right = *other
+	}
+	if r != nil && other != nil && !defsEqual(left.def, right.def) {
+		return nil, ErrDefMismatch
+	}
+	tags := make([]int, 0, len(left.m)+len(right.m))
+	for tag, fd := range left.m {
+		if fd.Has() {
+			tags = append(tags, tag)
+		}
+	}
+	for tag, fd := range right.m {
+		if fd.Has() && !left.m[tag].Has() {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Ints(tags)
+	var diffs []TagDiff
+	for _, tag := range tags {
+		lfd, rfd := left.m[tag], right.m[tag]
+		if lfd.Has() && rfd.Has() && fieldDataMapsEqual(map[int]*FieldData{tag: lfd}, map[int]*FieldData{tag: rfd}) {
+			continue
+		}
+		diffs = append(diffs, TagDiff{
+			Tag:       tag,
+			LeftData:  rawFieldData(lfd),
+			RightData: rawFieldData(rfd),
+		})
+	}
+	return diffs, nil
+}
+
+// rawFieldData returns the raw bytes of each value in fd, encoding the decoded fields of nested messages.
+func rawFieldData(fd *FieldData) [][]byte {
+	if !fd.Has() {
+		return nil
+	}
+	res := make([][]byte, 0, len(fd.data))
+	for _, d := range fd.data {
+		switch tv := d.(type) {
+		case []byte:
+			res = append(res, tv)
+		case map[int]*FieldData:
+			res = append(res, appendFieldDataMap(nil, tv))
+		}
+	}
+	return res
+}
+
+// appendFieldDataMap appends the Protobuf encoding of the fields in m, in ascending tag order, to buf.
+// Raw field data, which is stored under negative tags, is not included.
+func appendFieldDataMap(buf []byte, m map[int]*FieldData) []byte {
+	tags := make([]int, 0, len(m))
+	for tag, fd := range m {
+		if tag > 0 && fd.Has() {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Ints(tags)
+	for _, tag := range tags {
+		fd := m[tag]
+		for _, d := range fd.data {
+			buf = protowire.AppendTag(buf, protowire.Number(tag), protowire.Type(fd.wt))
+			switch tv := d.(type) {
+			case []byte:
+				if fd.wt == csproto.WireTypeLengthDelimited {
+					buf = protowire.AppendBytes(buf, tv)
+				} else {
+					buf = append(buf, tv...)
+				}
+			case map[int]*FieldData:
+				buf = protowire.AppendBytes(buf, appendFieldDataMap(nil, tv))
+			}
+		}
+	}
+	return buf
+}
+
+// Map returns the decoded fields in r keyed by tag, with each value converted to its most natural Go
+// representation based on the wire type: varint values are returned as int64, fixed32 as uint32,
+// fixed64 as uint64, and length-delimited values as []byte.  Nested messages are returned as
+// map[int]interface{}, recursively.
+//
+// If the decoded data contained more than one value for a tag, the value is a []interface{} containing
+// each value in the order they were decoded.
+//
+// As with [FieldData.BytesValue], the returned []byte values reference the original message data.
+func (r *DecodeResult) Map() (map[int]interface{}, error) {
+	if r == nil {
+		return map[int]interface{}{}, nil
+	}
+	return fieldDataMapToMap(r.m, nil)
+}
+
+// fieldDataMapToMap is the internal implementation of [DecodeResult.Map] for m, which is located at
+// the specified tag path.
+func fieldDataMapToMap(m map[int]*FieldData, path []int) (map[int]interface{}, error) {
+	res := make(map[int]interface{}, len(m))
+	for tag, fd := range m {
+		if !fd.Has() {
+			continue
+		}
+		vals := make([]interface{}, len(fd.data))
+		for i, d := range fd.data {
+			var err error
+			switch tv := d.(type) {
+			case map[int]*FieldData:
+				vals[i], err = fieldDataMapToMap(tv, append(path[:len(path):len(path)], tag))
+			case []byte:
+				vals[i], err = rawValueToInterface(fd.wt, tv)
+			default:
+				err = fmt.Errorf("unexpected field data type %T", d)
+			}
+			if err != nil {
+				return nil, newTagPathError(path, tag, err)
+			}
+		}
+		if len(vals) == 1 {
+			res[tag] = vals[0]
+		} else {
+			res[tag] = vals
+		}
+	}
+	return res, nil
+}
+
+// rawValueToInterface converts the raw bytes of a single field value with the specified wire type to
+// the corresponding Go type.
+func rawValueToInterface(wt csproto.WireType, v []byte) (interface{}, error) {
+	switch wt {
+	case csproto.WireTypeVarint:
+		n, _, err := csproto.DecodeVarint(v)
+		if err != nil {
+			return nil, err
+		}
+		return int64(n), nil
+	case csproto.WireTypeFixed32:
+		n, _, err := csproto.DecodeFixed32(v)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case csproto.WireTypeFixed64:
+		n, _, err := csproto.DecodeFixed64(v)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case csproto.WireTypeLengthDelimited:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported protobuf wire type (%v)", wt)
+	}
+}
+
+// FieldDataAll returns FieldData instances for all occurrences of the specified tag "path".
+//
+// Unlike FieldData(), which only follows the first occurrence of each nested message along the path,
+// FieldDataAll() follows every occurrence, which allows accessing the fields of repeated nested
+// messages.  The result contains one entry for each occurrence of the message that contains the last
+// tag in the path, so FieldDataAll(3, 1) returns the data for field 1 within each occurrence of the
+// nested message at field 3.
+func (r *DecodeResult) FieldDataAll(tags ...int) ([]*FieldData, error) {
+	if r == nil || len(r.m) == 0 {
+		return nil, ErrTagNotFound
+	}
+	if err := checkTagPath(tags); err != nil {
+		return nil, err
+	}
+	msgs := []map[int]*FieldData{r.m}
+	for ; len(tags) > 1; tags = tags[1:] {
+		var nested []map[int]*FieldData
+		for _, m := range msgs {
+			fd, ok := m[tags[0]]
+			if !ok {
+				continue
+			}
+			for _, d := range fd.data {
+				if nm, ok := d.(map[int]*FieldData); ok {
+					nested = append(nested, nm)
+				}
+			}
+		}
+		if len(nested) == 0 {
+			return nil, ErrTagNotFound
+		}
+		msgs = nested
+	}
+	var res []*FieldData
+	for _, m := range msgs {
+		if fd, ok := m[tags[0]]; ok && len(fd.data) > 0 {
+			res = append(res, fd)
+		}
+	}
+	if len(res) == 0 {
+		return nil, ErrTagNotFound
+	}
+	return res, nil
+}
+
+// NestedResultsAll returns a DecodeResult for each occurrence of the nested message at innerTag within
+// every occurrence of the nested message at outerTag, flattened into a single list.  For example, if
+// outerTag is a repeated Batch message and innerTag is a repeated Event field within Batch, the result
+// contains one entry for each Event across all of the batches.
+//
+// Both tags must be declared as nested messages in the [Def] passed to [Decode], otherwise they are not
+// decoded as messages and ErrTagNotFound is returned.
+//
+// The returned results reference data that is owned by r, so they are only valid until r is closed.
+// Calling Close() on them is a no-op and they cannot be passed to DecodeInto().
+func (r *DecodeResult) NestedResultsAll(outerTag, innerTag int) ([]*DecodeResult, error) {
+	if err := checkTagPath([]int{outerTag, innerTag}); err != nil {
+		return nil, err
+	}
+	if r == nil || len(r.m) == 0 {
+		return nil, ErrTagNotFound
+	}
+	outer, ok := r.m[outerTag]
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+	var res []*DecodeResult
+	for _, od := range outer.data {
+		om, ok := od.(map[int]*FieldData)
+		if !ok {
+			continue
+		}
+		inner, ok := om[innerTag]
+		if !ok {
+			continue
+		}
+		for _, id := range inner.data {
+			if im, ok := id.(map[int]*FieldData); ok {
+				res = append(res, &DecodeResult{m: im, borrowed: true})
+			}
+		}
+	}
+	if len(res) == 0 {
+		return nil, ErrTagNotFound
+	}
+	return res, nil
+}
+
+// WireType is a convenience method that returns the Protobuf wire type of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling WireType() on the result.  If the
+// field is not found, WireType returns -1 and the error from FieldData().
+func (r *DecodeResult) WireType(tag int) (csproto.WireType, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return -1, err
+	}
+	return fd.WireType(), nil
+}
+
+// BoolValue is a convenience method that returns the boolean value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling BoolValue() on the result.
+func (r *DecodeResult) BoolValue(tag int) (bool, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return false, err
+	}
+	return fd.BoolValue()
+}
+
+// BoolValues is a convenience method that returns the boolean values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling BoolValues() on the result.
+func (r *DecodeResult) BoolValues(tag int) ([]bool, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.BoolValues()
+}
+
+// StringValue is a convenience method that returns the string value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling StringValue() on the result.
+func (r *DecodeResult) StringValue(tag int) (string, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return "", err
+	}
+	return fd.StringValue()
+}
+
+// StringValues is a convenience method that returns the string values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling StringValues() on the result.
+func (r *DecodeResult) StringValues(tag int) ([]string, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.StringValues()
+}
+
+// BytesValue is a convenience method that returns the bytes value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling BytesValue() on the result.
+func (r *DecodeResult) BytesValue(tag int) ([]byte, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.BytesValue()
+}
+
+// BytesValues is a convenience method that returns the bytes values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling BytesValues() on the result.
+func (r *DecodeResult) BytesValues(tag int) ([][]byte, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.BytesValues()
+}
+
+// UInt32Value is a convenience method that returns the uint32 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling UInt32Value() on the result.
+func (r *DecodeResult) UInt32Value(tag int) (uint32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.UInt32Value()
+}
+
+// UInt32Values is a convenience method that returns the uint32 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling UInt32Values() on the result.
+func (r *DecodeResult) UInt32Values(tag int) ([]uint32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.UInt32Values()
+}
+
+// Int32Value is a convenience method that returns the int32 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Int32Value() on the result.
+func (r *DecodeResult) Int32Value(tag int) (int32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.Int32Value()
+}
+
+// Int32Values is a convenience method that returns the int32 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Int32Values() on the result.
+func (r *DecodeResult) Int32Values(tag int) ([]int32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Int32Values()
+}
+
+// SInt32Value is a convenience method that returns the sint32 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling SInt32Value() on the result.
+func (r *DecodeResult) SInt32Value(tag int) (int32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.SInt32Value()
+}
+
+// SInt32Values is a convenience method that returns the sint32 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling SInt32Values() on the result.
+func (r *DecodeResult) SInt32Values(tag int) ([]int32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.SInt32Values()
+}
+
+// UInt64Value is a convenience method that returns the uint64 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling UInt64Value() on the result.
+func (r *DecodeResult) UInt64Value(tag int) (uint64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.UInt64Value()
+}
+
+// UInt64Values is a convenience method that returns the uint64 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling UInt64Values() on the result.
+func (r *DecodeResult) UInt64Values(tag int) ([]uint64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.UInt64Values()
+}
+
+// Int64Value is a convenience method that returns the int64 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Int64Value() on the result.
+func (r *DecodeResult) Int64Value(tag int) (int64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.Int64Value()
+}
+
+// Int64Values is a convenience method that returns the int64 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Int64Values() on the result.
+func (r *DecodeResult) Int64Values(tag int) ([]int64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Int64Values()
+}
+
+// SInt64Value is a convenience method that returns the sint64 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling SInt64Value() on the result.
+func (r *DecodeResult) SInt64Value(tag int) (int64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.SInt64Value()
+}
+
+// SInt64Values is a convenience method that returns the sint64 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling SInt64Values() on the result.
+func (r *DecodeResult) SInt64Values(tag int) ([]int64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.SInt64Values()
+}
+
+// Fixed32Value is a convenience method that returns the fixed32 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Fixed32Value() on the result.
+func (r *DecodeResult) Fixed32Value(tag int) (uint32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.Fixed32Value()
+}
+
+// Fixed32Values is a convenience method that returns the fixed32 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Fixed32Values() on the result.
+func (r *DecodeResult) Fixed32Values(tag int) ([]uint32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Fixed32Values()
+}
+
+// Fixed64Value is a convenience method that returns the fixed64 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Fixed64Value() on the result.
+func (r *DecodeResult) Fixed64Value(tag int) (uint64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.Fixed64Value()
+}
+
+// Fixed64Values is a convenience method that returns the fixed64 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Fixed64Values() on the result.
+func (r *DecodeResult) Fixed64Values(tag int) ([]uint64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Fixed64Values()
+}
+
+// Float32Value is a convenience method that returns the float32 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Float32Value() on the result.
+func (r *DecodeResult) Float32Value(tag int) (float32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.Float32Value()
+}
+
+// Float32Values is a convenience method that returns the float32 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Float32Values() on the result.
+func (r *DecodeResult) Float32Values(tag int) ([]float32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Float32Values()
+}
+
+// Float64Value is a convenience method that returns the float64 value of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Float64Value() on the result.
+func (r *DecodeResult) Float64Value(tag int) (float64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.Float64Value()
+}
+
+// Float64Values is a convenience method that returns the float64 values of the field with the specified
+// tag.  It is equivalent to calling r.FieldData(tag) then calling Float64Values() on the result.
+func (r *DecodeResult) Float64Values(tag int) ([]float64, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Float64Values()
+}
+
+// EnumValue is a convenience method that returns the enum value of the field with the specified tag.
+// It is equivalent to calling r.FieldData(tag) then calling EnumValue() on the result.
+func (r *DecodeResult) EnumValue(tag int) (int32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return 0, err
+	}
+	return fd.EnumValue()
+}
+
+// EnumValues is a convenience method that returns the enum values of the field with the specified tag.
+// It is equivalent to calling r.FieldData(tag) then calling EnumValues() on the result.
+func (r *DecodeResult) EnumValues(tag int) ([]int32, error) {
+	fd, err := r.FieldData(tag)
+	if err != nil {
+		return nil, err
+	}
+	return fd.EnumValues()
+}
+
+// checkTagPath validates the tag "path" passed to FieldData() or FieldDataAll().
+func checkTagPath(tags []int) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("at least one tag key must be specified")
+	}
+	// special case:
+	// - negative tag values are used to extract the raw bytes of a field, but it must be the only
+	//   (or last) field in the path
+	for i := 0; i < len(tags)-1; i++ {
+		if tags[i] < 0 {
+			return fmt.Errorf("invalid tag in path at index %d, negative tags must be the last (or only) path item", i)
+		}
+	}
+	return nil
+}
+
+// capturedCount returns the number of values that have been captured for tag, including the values that
+// were captured as raw bytes under -tag if raw is true.
+func (r *DecodeResult) capturedCount(tag int, raw bool) int {
+	n := r.m[tag].Count()
+	if raw {
+		n = max(n, r.m[-1*tag].Count())
+	}
+	return n
+}
+
 // getOrAddFieldData is a helper to consolidate the logic of checking if a given tag exists in the
 // field data map and adding it if not.
 func (r *DecodeResult) getOrAddFieldData(tag int, wt csproto.WireType) (*FieldData, error) {
 	// first key: add a new entry and return
-	if len(r.m) == 0 {
+	if r.m == nil {
 		fd := &FieldData{
 			wt: wt,
 		}
@@ -194,11 +1491,15 @@ func (r *DecodeResult) getOrAddFieldData(tag int, wt csproto.WireType) (*FieldDa
 	}
 	// if the key doesn't exist, add a new entry
 	fd, exists := r.m[tag]
-	if !exists {
+	switch {
+	case !exists:
 		fd = &FieldData{
 			wt: wt,
 		}
 		r.m[tag] = fd
+	case len(fd.data) == 0:
+		// the entry was cleared by DecodeInto() so the wire type may be different
+		fd.wt = wt
 	}
 	// double-check wire type
 	if fd.wt != wt {
@@ -207,3 +1508,36 @@ func (r *DecodeResult) getOrAddFieldData(tag int, wt csproto.WireType) (*FieldDa
 
 	return fd, nil
 }
+
+// TagPathError is returned by [Decode] when an error occurs while decoding a field, and identifies the
+// tag "path" of that field.  For example, a path of [3, 1] refers to field 1 within the nested message
+// at field 3.
+type TagPathError struct {
+	// Path is the list of field tags leading to the field that could not be decoded
+	Path []int
+	// Err is the underlying error
+	Err error
+}
+
+// Error satisfies the error interface
+func (e *TagPathError) Error() string {
+	return fmt.Sprintf("decoding tag path %v: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TagPathError) Unwrap() error {
+	return e.Err
+}
+
+// newTagPathError wraps err in a *TagPathError for the field at tag within the message at path, unless
+// err already contains a *TagPathError from decoding a more deeply nested message.
+func newTagPathError(path []int, tag int, err error) error {
+	var tpe *TagPathError
+	if errors.As(err, &tpe) {
+		return err
+	}
+	p := make([]int, len(path)+1)
+	copy(p, path)
+	p[len(path)] = tag
+	return &TagPathError{Path: p, Err: err}
+}
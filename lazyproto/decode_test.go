@@ -1,12 +1,20 @@
 package lazyproto
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/CrowdStrike/csproto"
 	"github.com/CrowdStrike/csproto/prototest"
@@ -167,97 +175,2042 @@ func TestDecode(t *testing.T) {
 	})
 }
 
+func TestDecodeTagPathError(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name         string
+		data         []byte
+		def          Def
+		expectedPath []int
+	}{
+		{
+			name: "top-level field",
+			// field 1: string with a length that is past the end of the data
+			data:         []byte{(1 << 3) | 2, 0x05, 'a'},
+			def:          NewDef(1),
+			expectedPath: []int{1},
+		},
+		{
+			name: "skipped top-level field",
+			// field 2: string with a length that is past the end of the data
+			data:         []byte{(1 << 3), 0x01, (2 << 3) | 2, 0x05, 'a'},
+			def:          NewDef(1),
+			expectedPath: []int{2},
+		},
+		{
+			name: "nested field",
+			// field 3: nested message (4 bytes)
+			// . field 1: string with a length that is past the end of the nested data
+			data:         []byte{(3 << 3) | 2, 0x04, (1 << 3) | 2, 0x05, 'a', 'b'},
+			def:          Def{3: NewDef(1)},
+			expectedPath: []int{3, 1},
+		},
+		{
+			name: "deeply nested field",
+			// field 3: nested message (6 bytes)
+			// . field 2: nested message (4 bytes)
+			//   . field 1: varint with a truncated value
+			data:         []byte{(3 << 3) | 2, 0x06, (2 << 3) | 2, 0x04, (1 << 3), 0x80, 0x80, 0x80},
+			def:          Def{3: Def{2: NewDef(1)}},
+			expectedPath: []int{3, 2, 1},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := Decode(tc.data, tc.def)
+			defer func() { _ = res.Close() }()
+
+			var tpe *TagPathError
+			if assert.ErrorAs(t, err, &tpe) {
+				assert.Equal(t, tc.expectedPath, tpe.Path)
+				assert.NotNil(t, tpe.Unwrap())
+				assert.Contains(t, err.Error(), fmt.Sprintf("decoding tag path %v: ", tc.expectedPath))
+			}
+		})
+	}
+}
+
+func TestDecodeMany(t *testing.T) {
+	t.Parallel()
+	datas := [][]byte{
+		// field 1: varint 1
+		{(1 << 3), 0x01},
+		// empty message
+		{},
+		// field 1: varint 3, field 2: string "a"
+		{(1 << 3), 0x03, (2 << 3) | 2, 0x01, 'a'},
+	}
+	t.Run("all valid", func(t *testing.T) {
+		t.Parallel()
+		results, release, err := DecodeMany(datas, NewDef(1))
+		require.NoError(t, err)
+		defer release()
+
+		require.Len(t, results, len(datas))
+		for i, expected := range []int32{1, 0, 3} {
+			fd, err := results[i].FieldData(1)
+			if expected == 0 {
+				assert.ErrorIs(t, err, ErrTagNotFound)
+				continue
+			}
+			require.NoError(t, err)
+			v, err := fd.Int32Value()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, v, "incorrect value for message %d", i)
+		}
+	})
+	t.Run("invalid message", func(t *testing.T) {
+		t.Parallel()
+		invalid := append(append([][]byte{}, datas...), []byte{(1 << 3), 0xFF})
+		results, release, err := DecodeMany(invalid, NewDef(1))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "index 3")
+		assert.Nil(t, results)
+		assert.NotPanics(t, release)
+	})
+	t.Run("invalid def", func(t *testing.T) {
+		t.Parallel()
+		results, release, err := DecodeMany(datas, NewDef(-1))
+		assert.Error(t, err)
+		assert.Nil(t, results)
+		assert.NotPanics(t, release)
+	})
+}
+
+func TestDecodeWithFilterFunc(t *testing.T) {
+	t.Parallel()
+	data := []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: varint 1, field 2: varint 2 }
+		(3 << 3) | 2, 0x04, (1 << 3), 0x01, (2 << 3), 0x02,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1, 2)
+
+	t.Run("excluded fields are skipped", func(t *testing.T) {
+		t.Parallel()
+		var called []int
+		filter := func(tag int) bool {
+			called = append(called, tag)
+			return tag != 2
+		}
+		res, err := Decode(data, def, WithFilterFunc(filter))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		assert.Equal(t, []int{1, 2, 3}, called, "filter should only be called for top-level tags in the def")
+		assert.False(t, res.HasTag(2))
+		fd, err := res.FieldData(1)
+		require.NoError(t, err)
+		v, err := fd.UInt32Value()
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(150), v)
+		// nested tags are not filtered
+		fd, err = res.FieldData(3, 2)
+		require.NoError(t, err)
+		v, err = fd.UInt32Value()
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(2), v)
+	})
+	t.Run("fields must also be in the def", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(data, NewDef(1), WithFilterFunc(func(int) bool { return true }))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		assert.True(t, res.HasTag(1))
+		assert.False(t, res.HasTag(2))
+		assert.False(t, res.HasTag(3))
+	})
+	t.Run("exclude all fields", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(data, def, WithFilterFunc(func(int) bool { return false }))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		assert.Equal(t, 0, res.FieldCount())
+	})
+	t.Run("decode many", func(t *testing.T) {
+		t.Parallel()
+		results, release, err := DecodeMany([][]byte{data, data}, def, WithFilterFunc(func(tag int) bool { return tag == 3 }))
+		require.NoError(t, err)
+		defer release()
+
+		for _, res := range results {
+			assert.False(t, res.HasTag(1))
+			assert.False(t, res.HasTag(2))
+			assert.True(t, res.HasTag(3))
+		}
+	})
+}
+
+func TestDecodeWithErrorHandler(t *testing.T) {
+	t.Parallel()
+	data := []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: nested message { field 1: varint 1, field 1: fixed32 1 } (mismatched wire types)
+		(2 << 3) | 2, 0x07, (1 << 3), 0x01, (1 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+		// field 3: varint 1
+		(3 << 3), 0x01,
+		// field 1: fixed32 1 (mismatched wire types)
+		(1 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+		// field 4: varint 1
+		(4 << 3), 0x01,
+		// field 5: overflowed varint
+		(5 << 3), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01,
+	}
+	def := NewDef(1, 3, 4, 5)
+	def.NestedTag(2, 1)
+
+	t.Run("no handler returns the first error", func(t *testing.T) {
+		t.Parallel()
+		_, err := Decode(data, def)
+		var tpe *TagPathError
+		require.ErrorAs(t, err, &tpe)
+		assert.Equal(t, []int{2, 1}, tpe.Path)
+	})
+	t.Run("skip all errors", func(t *testing.T) {
+		t.Parallel()
+		var errTags []int
+		res, err := Decode(data, def, WithErrorHandler(func(tag int, _ csproto.WireType, _ error) bool {
+			errTags = append(errTags, tag)
+			return true
+		}))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		assert.Equal(t, []int{1, 1, 5}, errTags)
+		fd, err := res.FieldData(1)
+		require.NoError(t, err)
+		vs, err := fd.UInt32Values()
+		assert.NoError(t, err)
+		assert.Equal(t, []uint32{150}, vs, "the mismatched value should have been skipped")
+		fd, err = res.FieldData(2, 1)
+		require.NoError(t, err)
+		v, err := fd.UInt32Value()
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), v)
+		assert.True(t, res.HasTag(3))
+		assert.True(t, res.HasTag(4))
+		assert.False(t, res.HasTag(5))
+	})
+	t.Run("abort on overflow", func(t *testing.T) {
+		t.Parallel()
+		_, err := Decode(data, def, WithErrorHandler(func(_ int, _ csproto.WireType, err error) bool {
+			return !errors.Is(err, csproto.ErrValueOverflow)
+		}))
+		require.ErrorIs(t, err, csproto.ErrValueOverflow)
+		var tpe *TagPathError
+		require.ErrorAs(t, err, &tpe)
+		assert.Equal(t, []int{5}, tpe.Path)
+	})
+	t.Run("log then continue", func(t *testing.T) {
+		t.Parallel()
+		var log []string
+		res, err := Decode(data, def, WithErrorHandler(func(tag int, wt csproto.WireType, err error) bool {
+			log = append(log, fmt.Sprintf("tag %d (%s): %v", tag, wt, err))
+			return true
+		}))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		require.Len(t, log, 3)
+		assert.Contains(t, log[0], "tag 1 (fixed32): decoding tag path [2 1]")
+		assert.Contains(t, log[1], "tag 1 (fixed32): decoding tag path [1]")
+		assert.Contains(t, log[2], "tag 5 (varint): decoding tag path [5]")
+		assert.Equal(t, 4, res.FieldCount())
+	})
+}
+
+func TestDecodeContext(t *testing.T) {
+	t.Parallel()
+	data := []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: varint 1, field 2: varint 2 }
+		(3 << 3) | 2, 0x04, (1 << 3), 0x01, (2 << 3), 0x02,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1, 2)
+
+	t.Run("active context", func(t *testing.T) {
+		t.Parallel()
+		res, err := DecodeContext(context.Background(), data, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		expected, err := Decode(data, def)
+		require.NoError(t, err)
+		defer func() { _ = expected.Close() }()
+		assert.True(t, expected.Equal(&res))
+	})
+	t.Run("cancelled before decoding", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := DecodeContext(ctx, data, def)
+		assert.Equal(t, context.Canceled, err)
+	})
+	t.Run("cancelled while decoding", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var tags []int
+		// use the filter to cancel the context after the first field has been decoded
+		filter := func(tag int) bool {
+			tags = append(tags, tag)
+			cancel()
+			return true
+		}
+		_, err := DecodeContext(ctx, data, def, WithFilterFunc(filter))
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, []int{1}, tags, "decoding should stop at the next field boundary")
+	})
+	t.Run("deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		_, err := DecodeContext(ctx, data, def)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
+
+func TestDecodeWithMaxDepth(t *testing.T) {
+	t.Parallel()
+	// build data and a matching def with 4 levels of nested messages, each of which is
+	//	message Node {
+	//		int32 value = 1;
+	//		Node  child = 2;
+	//	}
+	var (
+		data []byte
+		def  = NewDef(1)
+		d    = def
+	)
+	for i := 0; i < 4; i++ {
+		d = d.NestedTag(2, 1)
+	}
+	for i := 4; i >= 0; i-- {
+		var node []byte
+		node = protowire.AppendTag(node, 1, protowire.VarintType)
+		node = protowire.AppendVarint(node, uint64(i))
+		if len(data) > 0 {
+			node = protowire.AppendTag(node, 2, protowire.BytesType)
+			node = protowire.AppendBytes(node, data)
+		}
+		data = node
+	}
+
+	t.Run("within the limit", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(data, def, WithMaxDepth(4))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		fd, err := res.FieldData(2, 2, 2, 2, 1)
+		require.NoError(t, err)
+		v, err := fd.Int32Value()
+		assert.NoError(t, err)
+		assert.Equal(t, int32(4), v)
+	})
+	t.Run("exceeds the limit", func(t *testing.T) {
+		t.Parallel()
+		_, err := Decode(data, def, WithMaxDepth(2))
+		assert.ErrorIs(t, err, ErrRecursionLimitExceeded)
+		var tpe *TagPathError
+		require.ErrorAs(t, err, &tpe)
+		assert.Equal(t, []int{2, 2, 2}, tpe.Path)
+	})
+	t.Run("no limit", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(data, def, WithMaxDepth(0))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		_, err = res.FieldData(2, 2, 2, 2, 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDecodeDeeplyNestedGroups(t *testing.T) {
+	t.Parallel()
+	// ~2MB of start group tags for field 1, which is not in the def and must be skipped
+	data := bytes.Repeat([]byte{0x0B}, 2<<20)
+	_, err := Decode(data, NewDef(2))
+	assert.ErrorIs(t, err, csproto.ErrInvalidGroupData)
+}
+
+func TestDecodeWithMaxNestedMessageSize(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: nested message (4 bytes)
+		// . field 1: nested message (2 bytes)
+		// . . field 1: varint 1
+		(1 << 3) | 2, 0x04, (1 << 3) | 2, 0x02, (1 << 3), 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+	}
+	def := NewDef(2)
+	def.NestedTag(1).NestedTag(1, 1)
+
+	t.Run("within the limit", func(t *testing.T) {
+		t.Parallel()
+		// field 2 is larger than the limit but is not a nested message
+		res, err := Decode(sampleMessage, def, WithMaxNestedMessageSize(4))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		_, err = res.FieldData(1, 1, 1)
+		assert.NoError(t, err)
+	})
+	t.Run("exceeds the limit", func(t *testing.T) {
+		t.Parallel()
+		_, err := Decode(sampleMessage, def, WithMaxNestedMessageSize(3))
+		assert.ErrorIs(t, err, ErrNestedMessageTooLarge)
+		var tpe *TagPathError
+		require.ErrorAs(t, err, &tpe)
+		assert.Equal(t, []int{1}, tpe.Path)
+	})
+	t.Run("skipped by error handler", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, def,
+			WithMaxNestedMessageSize(3),
+			WithErrorHandler(func(_ int, _ csproto.WireType, err error) bool {
+				return errors.Is(err, ErrNestedMessageTooLarge)
+			}))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		// the oversized nested message is skipped but the rest of the data is decoded
+		assert.False(t, res.HasTag(1))
+		s, err := res.StringValue(2)
+		require.NoError(t, err)
+		assert.Equal(t, "testing", s)
+	})
+	t.Run("no limit", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, def, WithMaxNestedMessageSize(0))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		_, err = res.FieldData(1, 1, 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDecodeWithTagLimit(t *testing.T) {
+	t.Parallel()
+	buf := make([]byte, 256)
+	enc := csproto.NewEncoder(buf)
+	for i := 0; i < 5; i++ {
+		enc.EncodeUInt32(1, uint32(i))
+		enc.EncodeString(2, fmt.Sprint(i))
+		enc.EncodeUInt32(3, uint32(i))
+	}
+	require.NoError(t, enc.Err())
+	sampleMessage := buf[:enc.Written()]
+	def := NewDef(1, 2, 3)
+
+	t.Run("limits are per tag", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, def, WithTagLimit(1, 2), WithTagLimit(2, 3))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		v1, err := res.UInt32Values(1)
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{0, 1}, v1)
+		v2, err := res.StringValues(2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0", "1", "2"}, v2)
+		v3, err := res.UInt32Values(3)
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{0, 1, 2, 3, 4}, v3)
+	})
+	t.Run("skipped values are not unknown fields", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1, 2), WithTagLimit(1, 1), WithUnknownFields())
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		assert.Equal(t, []int{3}, sortedKeys(res.UnknownFields()))
+		v1, err := res.UInt32Values(1)
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{0}, v1)
+	})
+	t.Run("limits apply to raw fields", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1, -2), WithTagLimit(2, 2))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		raw, err := res.BytesValues(-2)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("0"), []byte("1")}, raw)
+	})
+	t.Run("limits apply to fields with both parsed and raw values", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1, 2, -2), WithTagLimit(2, 1))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		v2, err := res.StringValues(2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0"}, v2)
+		raw, err := res.BytesValues(-2)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("0")}, raw)
+	})
+	t.Run("zero removes the limit", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, def, WithTagLimit(1, 1), WithTagLimit(1, 0))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		v1, err := res.UInt32Values(1)
+		require.NoError(t, err)
+		assert.Len(t, v1, 5)
+	})
+}
+
+func TestDecodeWithCloseHook(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: nested message { field 1: varint 1 }
+		(2 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1)
+	def.NestedTag(2, 1)
+
+	t.Run("hooks are called in order", func(t *testing.T) {
+		t.Parallel()
+		var calls []string
+		hook := func(name string) func(*DecodeResult) {
+			return func(r *DecodeResult) {
+				// the field data has already been cleared
+				assert.Equal(t, 0, r.FieldCount())
+				calls = append(calls, name)
+			}
+		}
+		res, err := Decode(sampleMessage, def, WithCloseHook(hook("first")), WithCloseHook(hook("second")))
+		require.NoError(t, err)
+		assert.Empty(t, calls)
+
+		require.NoError(t, res.Close())
+		assert.Equal(t, []string{"first", "second"}, calls)
+		// closing again does not call the hooks again
+		require.NoError(t, res.Close())
+		assert.Equal(t, []string{"first", "second"}, calls)
+	})
+	t.Run("empty data", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		res, err := Decode(nil, def, WithCloseHook(func(*DecodeResult) { calls++ }))
+		require.NoError(t, err)
+		require.NoError(t, res.Close())
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("decode many", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		results, release, err := DecodeMany([][]byte{sampleMessage, nil, sampleMessage}, def, WithCloseHook(func(*DecodeResult) { calls++ }))
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		release()
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("not called on decode error", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		_, err := Decode(sampleMessage[:5], def, WithCloseHook(func(*DecodeResult) { calls++ }))
+		require.Error(t, err)
+		assert.Equal(t, 0, calls)
+	})
+	t.Run("not called for copies", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		res, err := Decode(sampleMessage, def, WithCloseHook(func(*DecodeResult) { calls++ }))
+		require.NoError(t, err)
+		cp := res.Copy()
+		require.NoError(t, cp.Close())
+		assert.Equal(t, 0, calls)
+		require.NoError(t, res.Close())
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestDecodeWithDecodeObserver(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: nested message { field 1: varint 1 }
+		(2 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1)
+	def.NestedTag(2, 1)
+
+	type observation struct {
+		dur  time.Duration
+		size int
+	}
+	newObserver := func() (*[]observation, DecoderOption) {
+		var obs []observation
+		return &obs, WithDecodeObserver(func(dur time.Duration, messageBytes int) {
+			obs = append(obs, observation{dur: dur, size: messageBytes})
+		})
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+		obs, opt := newObserver()
+		res, err := Decode(sampleMessage, def, opt)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		// nested messages are not observed separately
+		require.Len(t, *obs, 1)
+		assert.Equal(t, len(sampleMessage), (*obs)[0].size)
+		assert.GreaterOrEqual(t, (*obs)[0].dur, time.Duration(0))
+
+		require.NoError(t, res.DecodeInto(sampleMessage[:3], opt))
+		require.Len(t, *obs, 2)
+		assert.Equal(t, 3, (*obs)[1].size)
+	})
+	t.Run("decode context", func(t *testing.T) {
+		t.Parallel()
+		obs, opt := newObserver()
+		res, err := DecodeContext(context.Background(), sampleMessage, def, opt)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		assert.Len(t, *obs, 1)
+	})
+	t.Run("decode many", func(t *testing.T) {
+		t.Parallel()
+		obs, opt := newObserver()
+		_, release, err := DecodeMany([][]byte{sampleMessage, nil, sampleMessage[:3]}, def, opt)
+		require.NoError(t, err)
+		defer release()
+		// the empty message is not observed
+		require.Len(t, *obs, 2)
+		assert.Equal(t, len(sampleMessage), (*obs)[0].size)
+		assert.Equal(t, 3, (*obs)[1].size)
+	})
+	t.Run("not called on error", func(t *testing.T) {
+		t.Parallel()
+		obs, opt := newObserver()
+		_, err := Decode(sampleMessage[:5], def, opt)
+		require.Error(t, err)
+		assert.Empty(t, *obs)
+	})
+}
+
 func TestDecodeResultFieldData(t *testing.T) {
 	t.Parallel()
 	var sampleMessage = []byte{
-		// field 1: varint boolean true
-		(1 << 3), 0x01,
+		// field 1: varint boolean true
+		(1 << 3), 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 0x74, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x67,
+		// field 3: nested message (10 bytes)
+		// . field 1: integer 5
+		// . field 2: string "nested"
+		(3 << 3) | 2, 0x0a, (1 << 3), 0x05, (2<<3 | 2), 0x06, 0x6e, 0x65, 0x73, 0x74, 0x65, 0x64,
+		// field 4: fixed32 1138
+		(4 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 5: float64 42.1138
+		(5 << 3) | 1, 0x74, 0x24, 0x97, 0xFF, 0x90, 0x0E, 0x45, 0x40,
+	}
+	t.Run("returns nil and not found error for nil result", func(t *testing.T) {
+		t.Parallel()
+
+		var res *DecodeResult
+		fd, err := res.FieldData(1)
+		assert.Nil(t, fd)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+	t.Run("returns nil and not found error for empty result", func(t *testing.T) {
+		t.Parallel()
+
+		var res DecodeResult
+		fd, err := res.FieldData(1)
+		assert.Nil(t, fd)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+	t.Run("returns nil and error when passed no tags", func(t *testing.T) {
+		t.Parallel()
+
+		def := NewDef(1)
+		res, _ := Decode(sampleMessage, def)
+		defer func() { _ = res.Close() }()
+		fd, err := res.FieldData()
+		assert.Nil(t, fd)
+		assert.Error(t, err)
+		assert.Contains(t, fmt.Sprint(err), "at least one tag key must be specified")
+	})
+	t.Run("returns nil and error when passed incorrect negative tag", func(t *testing.T) {
+		t.Parallel()
+
+		def := NewDef(1)
+		res, _ := Decode(sampleMessage, def)
+		defer func() { _ = res.Close() }()
+		fd, err := res.FieldData(1, -1, 1)
+		assert.Nil(t, fd)
+		assert.Error(t, err)
+		assert.Contains(t, fmt.Sprint(err), "negative tags must be the last (or only) path item")
+	})
+	t.Run("returns nil and not found error for unmatched path", func(t *testing.T) {
+		t.Parallel()
+
+		def := NewDef()
+		_ = def.NestedTag(3, 1)
+		res, _ := Decode(sampleMessage, def)
+		defer func() { _ = res.Close() }()
+		fd, err := res.FieldData(3, 3)
+		assert.Nil(t, fd)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+	t.Run("traverses 3 or more levels of nesting", func(t *testing.T) {
+		t.Parallel()
+
+		var deepMessage = []byte{
+			// field 2: nested message (12 bytes)
+			// . field 3: nested message (10 bytes)
+			// . . field 1: string "deep"
+			// . . field 2: nested message (2 bytes)
+			// . . . field 1: integer 7
+			(2 << 3) | 2, 0x0c, (3 << 3) | 2, 0x0a, (1 << 3) | 2, 0x04, 0x64, 0x65, 0x65, 0x70, (2 << 3) | 2, 0x02, (1 << 3), 0x07,
+		}
+		def := NewDef()
+		l3 := def.NestedTag(2).NestedTag(3, 1)
+		l3.NestedTag(2, 1)
+		res, err := Decode(deepMessage, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		fd, err := res.FieldData(2, 3, 1)
+		require.NoError(t, err)
+		s, err := fd.StringValue()
+		require.NoError(t, err)
+		assert.Equal(t, "deep", s)
+
+		fd, err = res.FieldData(2, 3, 2, 1)
+		require.NoError(t, err)
+		v, err := fd.Int32Value()
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), v)
+
+		for _, path := range [][]int{{2, 3, 3}, {2, 3, 1, 1}, {2, 1, 1}, {1, 3, 1}} {
+			fd, err = res.FieldData(path...)
+			assert.Nil(t, fd, "path %v", path)
+			assert.ErrorIs(t, err, ErrTagNotFound, "path %v", path)
+		}
+	})
+	t.Run("returns zero and not found error for nil field data", func(t *testing.T) {
+		t.Parallel()
+		var fd *FieldData
+
+		// NOTE: dylan.bourque - 2023-01-11
+		// . all the internal logic for all *Value() and *Values() methods is the same so we only need to test one
+		v, err := fd.UInt32Value()
+		assert.Equal(t, uint32(0), v, "scalar method should return 0")
+		assert.ErrorIs(t, err, ErrTagNotFound)
+
+		vs, err := fd.UInt32Values()
+		assert.Nil(t, vs, "slice method should return nil")
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+	t.Run("returns zero and not found error for empty field data", func(t *testing.T) {
+		t.Parallel()
+		var fd FieldData
+
+		// NOTE: dylan.bourque - 2023-01-11
+		// . all the internal logic for all *Value() and *Values() methods is the same so we only need to test one
+		v, err := fd.UInt32Value()
+		assert.Equal(t, uint32(0), v, "scalar method should return 0")
+		assert.ErrorIs(t, err, ErrTagNotFound)
+
+		vs, err := fd.UInt32Values()
+		assert.Nil(t, vs, "slice method should return nil")
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestDecodeResultFieldDataAll(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 1
+		(1 << 3), 0x01,
+		// field 3: nested message (5 bytes)
+		// . field 1: string "a"
+		// . field 2: varint 1
+		(3 << 3) | 2, 0x05, (1<<3 | 2), 0x01, 'a', (2 << 3), 0x01,
+		// field 3: nested message (3 bytes)
+		// . field 1: string "b"
+		(3 << 3) | 2, 0x03, (1<<3 | 2), 0x01, 'b',
+		// field 3: nested message (5 bytes)
+		// . field 1: string "c"
+		// . field 2: varint 3
+		(3 << 3) | 2, 0x05, (1<<3 | 2), 0x01, 'c', (2 << 3), 0x03,
+	}
+	def := NewDef(1)
+	_ = def.NestedTag(3, 1, 2)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	t.Run("all occurrences of nested field", func(t *testing.T) {
+		fds, err := res.FieldDataAll(3, 1)
+		require.NoError(t, err)
+		require.Len(t, fds, 3)
+		for i, expected := range []string{"a", "b", "c"} {
+			v, err := fds[i].StringValue()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, v, "mismatched value at index %d", i)
+		}
+	})
+	t.Run("nested field missing from some occurrences", func(t *testing.T) {
+		fds, err := res.FieldDataAll(3, 2)
+		require.NoError(t, err)
+		require.Len(t, fds, 2)
+		for i, expected := range []int32{1, 3} {
+			v, err := fds[i].Int32Value()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, v, "mismatched value at index %d", i)
+		}
+	})
+	t.Run("top-level field", func(t *testing.T) {
+		fds, err := res.FieldDataAll(1)
+		require.NoError(t, err)
+		require.Len(t, fds, 1)
+		v, err := fds[0].Int32Value()
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), v)
+	})
+	t.Run("tag not present", func(t *testing.T) {
+		fds, err := res.FieldDataAll(3, 4)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.Nil(t, fds)
+		fds, err = res.FieldDataAll(2, 1)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.Nil(t, fds)
+	})
+	t.Run("invalid tag path", func(t *testing.T) {
+		_, err := res.FieldDataAll()
+		assert.Error(t, err)
+		_, err = res.FieldDataAll(-3, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeResultHasTag(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 1
+		(1 << 3), 0x01,
+		// field 2: empty string
+		(2 << 3) | 2, 0x00,
+	}
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+		var res *DecodeResult
+		assert.False(t, res.HasTag(1))
+	})
+	t.Run("empty result", func(t *testing.T) {
+		t.Parallel()
+		var res DecodeResult
+		assert.False(t, res.HasTag(1))
+	})
+	t.Run("tag present", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1, 2, 3))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		assert.True(t, res.HasTag(1))
+		assert.True(t, res.HasTag(2), "an empty length-delimited field is still present")
+		assert.False(t, res.HasTag(3))
+	})
+	t.Run("tag present with no data", func(t *testing.T) {
+		t.Parallel()
+		res := DecodeResult{m: map[int]*FieldData{1: {wt: csproto.WireTypeLengthDelimited}}}
+		assert.False(t, res.HasTag(1))
+	})
+	t.Run("closed result", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1))
+		require.NoError(t, err)
+		_ = res.Close()
+		assert.False(t, res.HasTag(1))
+	})
+}
+
+func TestDecodeResultNestedResultsAll(t *testing.T) {
+	t.Parallel()
+	// message Batch { repeated Event events = 2; }
+	// message Event { int32 id = 1; }
+	// message Batches { repeated Batch batches = 3; }
+	event := func(id byte) []byte {
+		return []byte{(2 << 3) | 2, 0x02, (1 << 3), id}
+	}
+	batch := func(events ...[]byte) []byte {
+		var content []byte
+		for _, e := range events {
+			content = append(content, e...)
+		}
+		return append([]byte{(3 << 3) | 2, byte(len(content))}, content...)
+	}
+	var sampleMessage []byte
+	sampleMessage = append(sampleMessage, batch(event(1), event(2), event(3))...)
+	sampleMessage = append(sampleMessage, batch(event(4), event(5), event(6))...)
+	// field 4: varint 1
+	sampleMessage = append(sampleMessage, (4 << 3), 0x01)
+
+	def := NewDef(4)
+	def.NestedTag(3).NestedTag(2, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	t.Run("all nested results", func(t *testing.T) {
+		results, err := res.NestedResultsAll(3, 2)
+		require.NoError(t, err)
+		require.Len(t, results, 6)
+		for i, r := range results {
+			fd, err := r.FieldData(1)
+			require.NoError(t, err)
+			v, err := fd.Int32Value()
+			assert.NoError(t, err)
+			assert.Equal(t, int32(i+1), v)
+		}
+	})
+	t.Run("closing nested results does not affect the parent", func(t *testing.T) {
+		res, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
+
+		results, err := res.NestedResultsAll(3, 2)
+		require.NoError(t, err)
+		for _, r := range results {
+			assert.NoError(t, r.Close())
+			assert.Error(t, r.DecodeInto(sampleMessage))
+		}
+		// the nested data is still owned by, and readable through, the parent
+		results, err = res.NestedResultsAll(3, 2)
+		require.NoError(t, err)
+		require.Len(t, results, 6)
+		v, err := results[5].Int32Value(1)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(6), v)
+		assert.NoError(t, res.Close())
+
+		// subsequent decodes are not affected by the field data maps being released
+		res2, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
+		defer func() { _ = res2.Close() }()
+		v, err = res2.Int32Value(4)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), v)
+	})
+	t.Run("missing tags", func(t *testing.T) {
+		_, err := res.NestedResultsAll(5, 2)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		_, err = res.NestedResultsAll(3, 5)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+	t.Run("non-message tags", func(t *testing.T) {
+		_, err := res.NestedResultsAll(4, 1)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+	t.Run("nil result", func(t *testing.T) {
+		var r *DecodeResult
+		_, err := r.NestedResultsAll(3, 2)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestDecodeResultFieldCount(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 0
+		(1 << 3), 0x00,
+		// field 2: string "a"
+		(2 << 3) | 2, 0x01, 'a',
+		// field 3: varint 1
+		(3 << 3), 0x01,
+		// field 3: varint 2
+		(3 << 3), 0x02,
+	}
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+		var res *DecodeResult
+		assert.Equal(t, 0, res.FieldCount())
+	})
+	t.Run("empty result", func(t *testing.T) {
+		t.Parallel()
+		var res DecodeResult
+		assert.Equal(t, 0, res.FieldCount())
+	})
+	cases := []struct {
+		name     string
+		def      Def
+		expected int
+	}{
+		{name: "no matching fields", def: NewDef(4), expected: 0},
+		{name: "zero-valued field", def: NewDef(1), expected: 1},
+		{name: "multiple fields", def: NewDef(1, 2, 4), expected: 2},
+		{name: "repeated field counts once", def: NewDef(1, 2, 3), expected: 3},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := Decode(sampleMessage, tc.def)
+			require.NoError(t, err)
+			defer func() { _ = res.Close() }()
+			assert.Equal(t, tc.expected, res.FieldCount())
+		})
+	}
+}
+
+func TestDecodeResultTags(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 3: varint 1
+		(3 << 3), 0x01,
+		// field 1: varint 0
+		(1 << 3), 0x00,
+		// field 2: string "a"
+		(2 << 3) | 2, 0x01, 'a',
+		// field 3: varint 2
+		(3 << 3), 0x02,
+	}
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+		var res *DecodeResult
+		assert.Nil(t, res.Tags())
+		assert.False(t, res.HasTag(1))
+	})
+	t.Run("empty result", func(t *testing.T) {
+		t.Parallel()
+		var res DecodeResult
+		assert.Nil(t, res.Tags())
+		assert.False(t, res.HasTag(1))
+	})
+	cases := []struct {
+		name     string
+		def      Def
+		expected []int
+	}{
+		{name: "no matching fields", def: NewDef(4), expected: nil},
+		{name: "sorted tags", def: NewDef(1, 2, 3, 4), expected: []int{1, 2, 3}},
+		{name: "raw field data", def: NewDef(-2, 2, 3), expected: []int{-2, 2, 3}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := Decode(sampleMessage, tc.def)
+			require.NoError(t, err)
+			defer func() { _ = res.Close() }()
+			assert.Equal(t, tc.expected, res.Tags())
+			assert.Equal(t, res.FieldCount(), len(res.Tags()))
+			for _, tag := range tc.expected {
+				assert.True(t, res.HasTag(tag))
+			}
+			_ = res.Close()
+			assert.Nil(t, res.Tags(), "a closed result should have no tags")
+		})
+	}
+}
+
+func TestDecodeResultAllTags(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 3: varint 1
+		(3 << 3), 0x01,
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+	}
+	def := NewDef(5, 3, 1, -2)
+	def.NestedTag(4, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{-2, 1, 3, 4, 5}, res.AllTags())
+	assert.Equal(t, []int{1, 3}, res.Tags())
+
+	t.Run("empty data", func(t *testing.T) {
+		res, err := Decode(nil, def)
+		require.NoError(t, err)
+		assert.Equal(t, []int{-2, 1, 3, 4, 5}, res.AllTags())
+		assert.Empty(t, res.Tags())
+	})
+	t.Run("closed result", func(t *testing.T) {
+		require.NoError(t, res.Close())
+		assert.Nil(t, res.AllTags())
+	})
+	t.Run("nil result", func(t *testing.T) {
+		var nilResult *DecodeResult
+		assert.Nil(t, nilResult.AllTags())
+	})
+}
+
+func TestDecodeResultRange(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 3: varint 1
+		(3 << 3), 0x01,
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "a"
+		(2 << 3) | 2, 0x01, 'a',
+	}
+	res, err := Decode(sampleMessage, NewDef(1, 2, 3))
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	t.Run("visits all tags in order", func(t *testing.T) {
+		var tags []int
+		res.Range(func(tag int, fd *FieldData) bool {
+			tags = append(tags, tag)
+			expected, err := res.FieldData(tag)
+			require.NoError(t, err)
+			assert.Same(t, expected, fd)
+			return true
+		})
+		assert.Equal(t, []int{1, 2, 3}, tags)
+	})
+	t.Run("stops when fn returns false", func(t *testing.T) {
+		var tags []int
+		res.Range(func(tag int, _ *FieldData) bool {
+			tags = append(tags, tag)
+			return tag < 2
+		})
+		assert.Equal(t, []int{1, 2}, tags)
+	})
+	t.Run("nil result", func(t *testing.T) {
+		var nilResult *DecodeResult
+		nilResult.Range(func(int, *FieldData) bool {
+			t.Error("fn should not be called for a nil result")
+			return true
+		})
+	})
+}
+func TestDecodeResultUnknownFields(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "a"
+		(2 << 3) | 2, 0x01, 'a',
+		// field 3: fixed32 1
+		(3 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+		// field 2: string "b"
+		(2 << 3) | 2, 0x01, 'b',
+		// field 4: nested message { field 1: varint 1 }
+		(4 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+
+	t.Run("retained with option", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1), WithUnknownFields())
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		expected := map[int][][]byte{
+			2: {
+				[]byte("a"),
+				[]byte("b"),
+			},
+			3: {
+				{0x01, 0x00, 0x00, 0x00},
+			},
+			4: {
+				{(1 << 3), 0x01},
+			},
+		}
+		assert.Equal(t, expected, res.UnknownFields())
+		for tag, wt := range map[int]csproto.WireType{2: csproto.WireTypeLengthDelimited, 3: csproto.WireTypeFixed32, 4: csproto.WireTypeLengthDelimited} {
+			got, err := res.UnknownFieldWireType(tag)
+			assert.NoError(t, err)
+			assert.Equal(t, wt, got, "mismatched wire type for tag %d", tag)
+		}
+		_, err = res.UnknownFieldWireType(1)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		v, err := res.UInt32Value(1)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(150), v)
+
+		// re-encoding the known and unknown fields produces the original message
+		buf := make([]byte, len(sampleMessage))
+		enc := csproto.NewEncoder(buf)
+		enc.EncodeUInt32(1, v)
+		for _, f := range []struct {
+			tag int
+			raw []byte
+		}{{2, expected[2][0]}, {3, expected[3][0]}, {2, expected[2][1]}, {4, expected[4][0]}} {
+			wt, err := res.UnknownFieldWireType(f.tag)
+			require.NoError(t, err)
+			enc.EncodeRawField(f.tag, wt, f.raw)
+		}
+		assert.NoError(t, enc.Err())
+		assert.Equal(t, sampleMessage, buf)
+	})
+	t.Run("empty def", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(), WithUnknownFields())
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		assert.Equal(t, []int{1, 2, 3, 4}, sortedKeys(res.UnknownFields()))
+		assert.Len(t, res.UnknownFields()[2], 2)
+	})
+	t.Run("only top-level fields", func(t *testing.T) {
+		t.Parallel()
+		// field 1 of the nested message in field 4 is not in the nested def but only top-level fields are retained
+		def := NewDef(1, 2, 3)
+		def.NestedTag(4, 2)
+		res, err := Decode(sampleMessage, def, WithUnknownFields())
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		assert.Empty(t, res.UnknownFields())
+	})
+	t.Run("not retained by default", func(t *testing.T) {
+		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(1))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		assert.Nil(t, res.UnknownFields())
+	})
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+		var nilResult *DecodeResult
+		assert.Nil(t, nilResult.UnknownFields())
+		wt, err := nilResult.UnknownFieldWireType(1)
+		assert.Equal(t, csproto.WireType(-1), wt)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestDecodeResultMarshalProto(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "a"
+		(2 << 3) | 2, 0x01, 'a',
+		// field 3: fixed32 1
+		(3 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+		// field 2: string "b"
+		(2 << 3) | 2, 0x01, 'b',
+		// field 4: nested message (11 bytes)
+		// . field 1: varint 1
+		// . field 2: fixed64 2
+		(4 << 3) | 2, 0x0b, (1 << 3), 0x01, (2 << 3) | 1, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		def := NewDef(1, 2, 3, -4)
+		def.NestedTag(4, 1, 2)
+		res, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		data, err := res.MarshalProto()
+		require.NoError(t, err)
+		// fields are written in tag order
+		expected := []byte{
+			(1 << 3), 0x96, 0x01,
+			(2 << 3) | 2, 0x01, 'a',
+			(2 << 3) | 2, 0x01, 'b',
+			(3 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+			(4 << 3) | 2, 0x0b, (1 << 3), 0x01, (2 << 3) | 1, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		}
+		assert.Equal(t, expected, data)
+
+		res2, err := Decode(data, def)
+		require.NoError(t, err)
+		defer func() { _ = res2.Close() }()
+		assert.True(t, res.Equal(&res2), "re-encoded data should decode to the same result")
+	})
+	t.Run("projection", func(t *testing.T) {
+		t.Parallel()
+		def := NewDef(2)
+		def.NestedTag(4, 2)
+		res, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		data, err := res.MarshalProto()
+		require.NoError(t, err)
+		expected := []byte{
+			(2 << 3) | 2, 0x01, 'a',
+			(2 << 3) | 2, 0x01, 'b',
+			(4 << 3) | 2, 0x09, (2 << 3) | 1, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		}
+		assert.Equal(t, expected, data)
+
+		dec := csproto.NewDecoder(data)
+		var strs []string
+		for dec.More() {
+			tag, wt, err := dec.DecodeTag()
+			require.NoError(t, err)
+			if tag != 2 {
+				_, err = dec.Skip(tag, wt)
+				require.NoError(t, err)
+				continue
+			}
+			s, err := dec.DecodeString()
+			require.NoError(t, err)
+			strs = append(strs, s)
+		}
+		assert.Equal(t, []string{"a", "b"}, strs)
+	})
+	t.Run("empty result", func(t *testing.T) {
+		t.Parallel()
+		var res DecodeResult
+		data, err := res.MarshalProto()
+		assert.NoError(t, err)
+		assert.Empty(t, data)
+
+		var nilResult *DecodeResult
+		data, err = nilResult.MarshalProto()
+		assert.NoError(t, err)
+		assert.Empty(t, data)
+	})
+}
+
+func TestDecodeResultCopy(t *testing.T) {
+	t.Parallel()
+	sampleMessage := []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: fixed32 1138 }
+		(3 << 3) | 2, 0x05, (1 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 4: varint 1
+		(4 << 3), 0x01,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1)
+	res, err := Decode(sampleMessage, def, WithUnknownFields())
+	require.NoError(t, err)
+	expected := res.String()
+
+	cp := res.Copy()
+	assert.True(t, res.Equal(cp))
+	assert.Equal(t, res.UnknownFields(), cp.UnknownFields())
+
+	// the copy should be unaffected by closing the original and by changes to the source data
+	_ = res.Close()
+	for i := range sampleMessage {
+		sampleMessage[i] = 0xFF
+	}
+	assert.Equal(t, expected, cp.String())
+	assert.Equal(t, map[int][][]byte{4: {{0x01}}}, cp.UnknownFields())
+	wt, err := cp.UnknownFieldWireType(4)
+	assert.NoError(t, err)
+	assert.Equal(t, csproto.WireTypeVarint, wt)
+
+	// closing the copy is a no-op
+	require.NoError(t, cp.Close())
+	fd, err := cp.FieldData(3, 1)
+	require.NoError(t, err)
+	v, err := fd.Fixed32Value()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1138), v)
+
+	t.Run("nil result", func(t *testing.T) {
+		var nilResult *DecodeResult
+		cp := nilResult.Copy()
+		require.NotNil(t, cp)
+		assert.Equal(t, 0, cp.FieldCount())
+		assert.NoError(t, cp.Close())
+	})
+}
+
+func TestDecodeResultDecodeInto(t *testing.T) {
+	t.Parallel()
+	var (
+		msg1 = []byte{
+			// field 1: varint 150
+			(1 << 3), 0x96, 0x01,
+			// field 2: string "one"
+			(2 << 3) | 2, 0x03, 'o', 'n', 'e',
+			// field 3: nested message { field 1: varint 1 }
+			(3 << 3) | 2, 0x02, (1 << 3), 0x01,
+		}
+		msg2 = []byte{
+			// field 2: string "two"
+			(2 << 3) | 2, 0x03, 't', 'w', 'o',
+			// field 2: string "2"
+			(2 << 3) | 2, 0x01, '2',
+			// field 1: fixed32 1138
+			(1 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+			// field 4: varint 1
+			(4 << 3), 0x01,
+		}
+	)
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1)
+
+	res, err := Decode(msg1, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	require.NoError(t, res.DecodeInto(msg2, WithUnknownFields()))
+	expected, err := Decode(msg2, def, WithUnknownFields())
+	require.NoError(t, err)
+	defer func() { _ = expected.Close() }()
+	assert.True(t, res.Equal(&expected), "expected %s, got %s", expected.String(), res.String())
+	assert.Equal(t, expected.UnknownFields(), res.UnknownFields())
+	assert.False(t, res.HasTag(3))
+	assert.Equal(t, []int{1, 2}, res.Tags())
+	v, err := res.Fixed32Value(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1138), v)
+
+	// decoding the original message again restores the original values
+	require.NoError(t, res.DecodeInto(msg1))
+	assert.Nil(t, res.UnknownFields())
+	s, err := res.StringValue(2)
+	require.NoError(t, err)
+	assert.Equal(t, "one", s)
+	fd, err := res.FieldData(3, 1)
+	require.NoError(t, err)
+	n, err := fd.UInt32Value()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), n)
+
+	t.Run("invalid data", func(t *testing.T) {
+		res, err := Decode(msg1, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		err = res.DecodeInto(msg2[:4])
+		assert.Error(t, err)
+		assert.Equal(t, 0, res.FieldCount())
+	})
+	t.Run("nil result", func(t *testing.T) {
+		var nilResult *DecodeResult
+		assert.Error(t, nilResult.DecodeInto(msg1))
+	})
+}
+
+func sortedKeys(m map[int][][]byte) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func TestDecodeResultEqual(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: varint 1 }
+		(3 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1)
+	decode := func(t *testing.T, data []byte, def Def) *DecodeResult {
+		t.Helper()
+		res, err := Decode(data, def)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Close() })
+		return &res
+	}
+
+	t.Run("equal results", func(t *testing.T) {
+		t.Parallel()
+		r1 := decode(t, sampleMessage, def)
+		r2 := decode(t, append([]byte(nil), sampleMessage...), def)
+		assert.True(t, r1.Equal(r2))
+		assert.True(t, r2.Equal(r1))
+	})
+	t.Run("different tags", func(t *testing.T) {
+		t.Parallel()
+		r1 := decode(t, sampleMessage, def)
+		r2 := decode(t, sampleMessage, NewDef(1, 2))
+		assert.False(t, r1.Equal(r2))
+		assert.False(t, r2.Equal(r1))
+	})
+	t.Run("different values", func(t *testing.T) {
+		t.Parallel()
+		other := append([]byte(nil), sampleMessage...)
+		// change the nested value from 1 to 2
+		other[len(other)-1] = 0x02
+		r1 := decode(t, sampleMessage, def)
+		r2 := decode(t, other, def)
+		assert.False(t, r1.Equal(r2))
+	})
+	t.Run("different order of repeated values", func(t *testing.T) {
+		t.Parallel()
+		r1 := decode(t, []byte{(1 << 3), 0x01, (1 << 3), 0x02}, NewDef(1))
+		r2 := decode(t, []byte{(1 << 3), 0x02, (1 << 3), 0x01}, NewDef(1))
+		assert.False(t, r1.Equal(r2))
+	})
+	t.Run("empty results", func(t *testing.T) {
+		t.Parallel()
+		var nilResult *DecodeResult
+		r1 := decode(t, nil, def)
+		r2 := decode(t, sampleMessage, NewDef(4))
+		assert.True(t, r1.Equal(r2))
+		assert.True(t, r1.Equal(nilResult))
+		assert.True(t, nilResult.Equal(r1))
+		assert.False(t, nilResult.Equal(decode(t, sampleMessage, def)))
+	})
+}
+
+func TestDecodeResultDiff(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: nested message { field 1: varint 1, field 2: varint 2 }
+		(3 << 3) | 2, 0x04, (1 << 3), 0x01, (2 << 3), 0x02,
+	}
+	def := NewDef(1, 2)
+	def.NestedTag(3, 1)
+	decode := func(t *testing.T, data []byte, def Def) *DecodeResult {
+		t.Helper()
+		res, err := Decode(data, def)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Close() })
+		return &res
+	}
+
+	t.Run("no difference", func(t *testing.T) {
+		t.Parallel()
+		diffs, err := decode(t, sampleMessage, def).Diff(decode(t, sampleMessage, def))
+		assert.NoError(t, err)
+		assert.Empty(t, diffs)
+	})
+	t.Run("one-sided tag", func(t *testing.T) {
+		t.Parallel()
+		// only field 1
+		other := decode(t, sampleMessage[:3], def)
+		diffs, err := decode(t, sampleMessage, def).Diff(other)
+		assert.NoError(t, err)
+		expected := []TagDiff{
+			{Tag: 2, LeftData: [][]byte{[]byte("testing")}},
+			// only field 1 of the nested message is decoded
+			{Tag: 3, LeftData: [][]byte{{(1 << 3), 0x01}}},
+		}
+		assert.Equal(t, expected, diffs)
+
+		diffs, err = other.Diff(decode(t, sampleMessage, def))
+		assert.NoError(t, err)
+		expected = []TagDiff{
+			{Tag: 2, RightData: [][]byte{[]byte("testing")}},
+			{Tag: 3, RightData: [][]byte{{(1 << 3), 0x01}}},
+		}
+		assert.Equal(t, expected, diffs)
+	})
+	t.Run("different values", func(t *testing.T) {
+		t.Parallel()
+		other := append([]byte(nil), sampleMessage...)
+		// field 1: varint 151
+		other[1] = 0x97
+		// nested field 1: varint 3
+		other[len(other)-3] = 0x03
+		diffs, err := decode(t, sampleMessage, def).Diff(decode(t, other, def))
+		assert.NoError(t, err)
+		expected := []TagDiff{
+			{Tag: 1, LeftData: [][]byte{{0x96, 0x01}}, RightData: [][]byte{{0x97, 0x01}}},
+			{Tag: 3, LeftData: [][]byte{{(1 << 3), 0x01}}, RightData: [][]byte{{(1 << 3), 0x03}}},
+		}
+		assert.Equal(t, expected, diffs)
+	})
+	t.Run("different repeated values", func(t *testing.T) {
+		t.Parallel()
+		r1 := decode(t, []byte{(1 << 3), 0x01, (1 << 3), 0x02}, NewDef(1))
+		r2 := decode(t, []byte{(1 << 3), 0x01}, NewDef(1))
+		diffs, err := r1.Diff(r2)
+		assert.NoError(t, err)
+		assert.Equal(t, []TagDiff{{Tag: 1, LeftData: [][]byte{{0x01}, {0x02}}, RightData: [][]byte{{0x01}}}}, diffs)
+	})
+	t.Run("empty results", func(t *testing.T) {
+		t.Parallel()
+		var nilResult *DecodeResult
+		diffs, err := decode(t, nil, def).Diff(decode(t, nil, def))
+		assert.NoError(t, err)
+		assert.Empty(t, diffs)
+		diffs, err = nilResult.Diff(decode(t, sampleMessage[:3], def))
+		assert.NoError(t, err)
+		assert.Equal(t, []TagDiff{{Tag: 1, RightData: [][]byte{{0x96, 0x01}}}}, diffs)
+	})
+	t.Run("different defs", func(t *testing.T) {
+		t.Parallel()
+		_, err := decode(t, sampleMessage, def).Diff(decode(t, sampleMessage, NewDef(1, 2, 3)))
+		assert.ErrorIs(t, err, ErrDefMismatch)
+		_, err = decode(t, sampleMessage, def).Diff(decode(t, nil, NewDef(1)))
+		assert.ErrorIs(t, err, ErrDefMismatch)
+	})
+}
+
+func TestDecodeResultMap(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
 		// field 2: string "testing"
-		(2 << 3) | 2, 0x07, 0x74, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x67,
-		// field 3: nested message (10 bytes)
-		// . field 1: integer 5
-		// . field 2: string "nested"
-		(3 << 3) | 2, 0x0a, (1 << 3), 0x05, (2<<3 | 2), 0x06, 0x6e, 0x65, 0x73, 0x74, 0x65, 0x64,
-		// field 4: fixed32 1138
-		(4 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
-		// field 5: float64 42.1138
-		(5 << 3) | 1, 0x74, 0x24, 0x97, 0xFF, 0x90, 0x0E, 0x45, 0x40,
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: fixed32 1138
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 4: fixed64 1138
+		(4 << 3) | 1, 0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// field 5: varint 1, repeated
+		(5 << 3), 0x01,
+		(5 << 3), 0x02,
+		// field 6: nested message { field 1: varint 1 }
+		(6 << 3) | 2, 0x02, (1 << 3), 0x01,
+		// field 6: nested message { field 1: varint 2, field 2: string "a" }
+		(6 << 3) | 2, 0x05, (1 << 3), 0x02, (2 << 3) | 2, 0x01, 'a',
 	}
-	t.Run("returns nil and not found error for nil result", func(t *testing.T) {
+	t.Run("all wire types", func(t *testing.T) {
+		t.Parallel()
+		def := NewDef(1, 2, 3, 4, 5)
+		def.NestedTag(6, 1, 2)
+		res, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
+
+		got, err := res.Map()
+		require.NoError(t, err)
+		expected := map[int]interface{}{
+			1: int64(150),
+			2: []byte("testing"),
+			3: uint32(1138),
+			4: uint64(1138),
+			5: []interface{}{int64(1), int64(2)},
+			6: []interface{}{
+				map[int]interface{}{1: int64(1)},
+				map[int]interface{}{1: int64(2), 2: []byte("a")},
+			},
+		}
+		assert.Equal(t, expected, got)
+	})
+	t.Run("nested message without def", func(t *testing.T) {
 		t.Parallel()
+		res, err := Decode(sampleMessage, NewDef(6))
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
 
+		got, err := res.Map()
+		require.NoError(t, err)
+		expected := map[int]interface{}{
+			6: []interface{}{
+				[]byte{(1 << 3), 0x01},
+				[]byte{(1 << 3), 0x02, (2 << 3) | 2, 0x01, 'a'},
+			},
+		}
+		assert.Equal(t, expected, got)
+	})
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
 		var res *DecodeResult
-		fd, err := res.FieldData(1)
-		assert.Nil(t, fd)
-		assert.ErrorIs(t, err, ErrTagNotFound)
+		got, err := res.Map()
+		assert.NoError(t, err)
+		assert.Empty(t, got)
 	})
-	t.Run("returns nil and not found error for empty result", func(t *testing.T) {
+	t.Run("invalid data", func(t *testing.T) {
 		t.Parallel()
+		res := DecodeResult{m: map[int]*FieldData{
+			1: {wt: csproto.WireTypeFixed32, data: []any{[]byte{0x01}}},
+		}}
+		_, err := res.Map()
+		var tpe *TagPathError
+		assert.ErrorAs(t, err, &tpe)
+	})
+}
 
+func TestDecodeResultString(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: fixed32 1138
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 3: fixed32 1
+		(3 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+		// field 4: fixed64 1138
+		(4 << 3) | 1, 0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// field 5: nested message { field 1: varint 1, field 2: string "a" }
+		(5 << 3) | 2, 0x05, (1 << 3), 0x01, (2 << 3) | 2, 0x01, 'a',
+	}
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+		var res *DecodeResult
+		assert.Equal(t, "<nil>", res.String())
+	})
+	t.Run("empty result", func(t *testing.T) {
+		t.Parallel()
 		var res DecodeResult
-		fd, err := res.FieldData(1)
-		assert.Nil(t, fd)
-		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.Equal(t, "", res.String())
 	})
-	t.Run("returns nil and error when passed no tags", func(t *testing.T) {
+	t.Run("all wire types", func(t *testing.T) {
 		t.Parallel()
-
-		def := NewDef(1)
-		res, _ := Decode(sampleMessage, def)
+		def := NewDef(1, 2, 3, 4)
+		def.NestedTag(5, 1, 2)
+		res, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
 		defer func() { _ = res.Close() }()
-		fd, err := res.FieldData()
-		assert.Nil(t, fd)
-		assert.Error(t, err)
-		assert.Contains(t, fmt.Sprint(err), "at least one tag key must be specified")
+
+		expected := "" +
+			"tag 1 [varint]: 0x96\n" +
+			"tag 2 [length-delimited]: \"testing\"\n" +
+			"tag 3 [fixed32]: 0x00000472, 0x00000001\n" +
+			"tag 4 [fixed64]: 0x0000000000000472\n" +
+			"tag 5 [length-delimited]: {tag 1 [varint]: 0x1; tag 2 [length-delimited]: \"a\"}"
+		assert.Equal(t, expected, res.String())
+		assert.Equal(t, expected, fmt.Sprintf("%v", &res))
 	})
-	t.Run("returns nil and error when passed incorrect negative tag", func(t *testing.T) {
-		t.Parallel()
+}
 
-		def := NewDef(1)
-		res, _ := Decode(sampleMessage, def)
-		defer func() { _ = res.Close() }()
-		fd, err := res.FieldData(1, -1, 1)
-		assert.Nil(t, fd)
-		assert.Error(t, err)
-		assert.Contains(t, fmt.Sprint(err), "negative tags must be the last (or only) path item")
+func TestDecodeResultDebugString(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 1: varint 1
+		(1 << 3), 0x01,
+		// field 2: string "0123456789abcdefghij"
+		(2 << 3) | 2, 0x14, '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j',
+		// field 3: nested message { field 1: fixed32 1138, field 2: nested message { field 1: varint 1 } }
+		(3 << 3) | 2, 0x09, (1 << 3) | 5, 0x72, 0x04, 0x00, 0x00, (2 << 3) | 2, 0x02, (1 << 3), 0x01,
+		// field 3: nested message { field 1: fixed32 1 }
+		(3 << 3) | 2, 0x05, (1 << 3) | 5, 0x01, 0x00, 0x00, 0x00,
+	}
+	t.Run("nil result", func(t *testing.T) {
+		t.Parallel()
+		var res *DecodeResult
+		assert.Equal(t, "<nil>", res.DebugString())
 	})
-	t.Run("returns nil and not found error for unmatched path", func(t *testing.T) {
+	t.Run("empty result", func(t *testing.T) {
 		t.Parallel()
-
-		def := NewDef()
-		_ = def.NestedTag(3, 1)
-		res, _ := Decode(sampleMessage, def)
-		defer func() { _ = res.Close() }()
-		fd, err := res.FieldData(3, 3)
-		assert.Nil(t, fd)
-		assert.ErrorIs(t, err, ErrTagNotFound)
+		var res DecodeResult
+		assert.Equal(t, "", res.DebugString())
 	})
-	t.Run("returns zero and not found error for nil field data", func(t *testing.T) {
+	t.Run("nested messages", func(t *testing.T) {
 		t.Parallel()
-		var fd *FieldData
+		def := NewDef(1, 2)
+		def.NestedTag(3, 1).NestedTag(2, 1)
+		res, err := Decode(sampleMessage, def)
+		require.NoError(t, err)
+		defer func() { _ = res.Close() }()
 
-		// NOTE: dylan.bourque - 2023-01-11
-		// . all the internal logic for all *Value() and *Values() methods is the same so we only need to test one
-		v, err := fd.UInt32Value()
-		assert.Equal(t, uint32(0), v, "scalar method should return 0")
-		assert.ErrorIs(t, err, ErrTagNotFound)
+		expected := "" +
+			"tag 1 [varint] count=2: len=2 [96 01], len=1 [01]\n" +
+			"tag 2 [length-delimited] count=1: len=20 [30 31 32 33 34 35 36 37 38 39 61 62 63 64 65 66 ...]\n" +
+			"tag 3 [length-delimited] count=2: {\n" +
+			"  tag 1 [fixed32] count=1: len=4 [72 04 00 00]\n" +
+			"  tag 2 [length-delimited] count=1: {\n" +
+			"    tag 1 [varint] count=1: len=1 [01]\n" +
+			"  }\n" +
+			"}, {\n" +
+			"  tag 1 [fixed32] count=1: len=4 [01 00 00 00]\n" +
+			"}"
+		assert.Equal(t, expected, res.DebugString())
+	})
+}
 
-		vs, err := fd.UInt32Values()
-		assert.Nil(t, vs, "slice method should return nil")
-		assert.ErrorIs(t, err, ErrTagNotFound)
+func TestDecodeResultDump(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint -1
+		(1 << 3), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01,
+		// field 2: string "hi"
+		(2 << 3) | 2, 0x02, 'h', 'i',
+		// field 3: fixed32 1138
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 4: fixed64 1138
+		(4 << 3) | 1, 0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// field 5: nested message { field 1: varint 1 }
+		(5 << 3) | 2, 0x02, (1 << 3), 0x01,
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+	}
+	def := NewDef(1, 2, 3, 4, -5)
+	def.NestedTag(5, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	expected := "" +
+		"tag: 1, wire type: varint\n" +
+		"  varint: -1\n" +
+		"tag: 1, wire type: varint\n" +
+		"  varint: 150\n" +
+		"tag: 2, wire type: length-delimited\n" +
+		"  length: 2\n" +
+		"  [0x68,0x69]\n" +
+		"tag: 3, wire type: fixed32\n" +
+		"  fixed32: 1138\n" +
+		"tag: 4, wire type: fixed64\n" +
+		"  fixed64: 1138\n" +
+		"tag: 5, wire type: length-delimited\n" +
+		"  length: 2\n" +
+		"  tag: 1, wire type: varint\n" +
+		"    varint: 1\n"
+	var sb strings.Builder
+	n, err := res.Dump().WriteTo(&sb)
+	require.NoError(t, err)
+	assert.Equal(t, expected, sb.String())
+	assert.Equal(t, int64(len(expected)), n)
+
+	t.Run("nil result", func(t *testing.T) {
+		var nilResult *DecodeResult
+		var sb strings.Builder
+		n, err := nilResult.Dump().WriteTo(&sb)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+		assert.Empty(t, sb.String())
 	})
-	t.Run("returns zero and not found error for empty field data", func(t *testing.T) {
-		t.Parallel()
-		var fd FieldData
+}
 
-		// NOTE: dylan.bourque - 2023-01-11
-		// . all the internal logic for all *Value() and *Values() methods is the same so we only need to test one
-		v, err := fd.UInt32Value()
-		assert.Equal(t, uint32(0), v, "scalar method should return 0")
+func TestFieldDataString(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 1: varint 1
+		(1 << 3), 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: string "0123456789abcdefghij"
+		(3 << 3) | 2, 0x14, '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j',
+		// field 4: fixed32 1138
+		(4 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 5: fixed64 1138
+		(5 << 3) | 1, 0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// field 6: nested message { field 1: varint 1 }
+		(6 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1, 2, 3, 4, 5)
+	def.NestedTag(6, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	cases := []struct {
+		name     string
+		tag      int
+		expected string
+	}{
+		{name: "varint", tag: 1, expected: "varint: 0x96 (150), 0x1 (1)"},
+		{name: "length-delimited", tag: 2, expected: "length-delimited: len=7 [74 65 73 74 69 6e 67]"},
+		{name: "truncated length-delimited", tag: 3, expected: "length-delimited: len=20 [30 31 32 33 34 35 36 37 38 39 61 62 63 64 65 66 ...]"},
+		{name: "fixed32", tag: 4, expected: "fixed32: [72 04 00 00]"},
+		{name: "fixed64", tag: 5, expected: "fixed64: [72 04 00 00 00 00 00 00]"},
+		{name: "nested message", tag: 6, expected: "length-delimited: {tag 1 [varint]: 0x1 (1)}"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fd, err := res.FieldData(tc.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, fd.String())
+		})
+	}
+	t.Run("nil field data", func(t *testing.T) {
+		var fd *FieldData
+		assert.Equal(t, "<nil>", fd.String())
+	})
+}
+
+func TestFieldDataWireType(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: fixed32 1138
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 4: fixed64 1138
+		(4 << 3) | 1, 0x72, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// field 5: nested message { field 1: varint 1 }
+		(5 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1, 2, 3, 4, -5)
+	def.NestedTag(5, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	cases := []struct {
+		name     string
+		tags     []int
+		expected csproto.WireType
+	}{
+		{name: "varint", tags: []int{1}, expected: csproto.WireTypeVarint},
+		{name: "length-delimited", tags: []int{2}, expected: csproto.WireTypeLengthDelimited},
+		{name: "fixed32", tags: []int{3}, expected: csproto.WireTypeFixed32},
+		{name: "fixed64", tags: []int{4}, expected: csproto.WireTypeFixed64},
+		{name: "nested message", tags: []int{5}, expected: csproto.WireTypeLengthDelimited},
+		{name: "raw nested message", tags: []int{-5}, expected: csproto.WireTypeLengthDelimited},
+		{name: "nested field", tags: []int{5, 1}, expected: csproto.WireTypeVarint},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fd, err := res.FieldData(tc.tags...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, fd.WireType())
+			if len(tc.tags) == 1 {
+				wt, err := res.WireType(tc.tags[0])
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, wt)
+			}
+		})
+	}
+	t.Run("nil field data", func(t *testing.T) {
+		var fd *FieldData
+		assert.Equal(t, csproto.WireType(-1), fd.WireType())
+	})
+	t.Run("missing tag", func(t *testing.T) {
+		wt, err := res.WireType(6)
+		assert.Equal(t, csproto.WireType(-1), wt)
 		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
 
-		vs, err := fd.UInt32Values()
-		assert.Nil(t, vs, "slice method should return nil")
+func TestFieldDataCount(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 1
+		(1 << 3), 0x01,
+		// field 2: varint 150
+		(2 << 3), 0x96, 0x01,
+		// field 2: varint 1
+		(2 << 3), 0x01,
+		// field 2: varint 2
+		(2 << 3), 0x02,
+		// field 3: packed varints [1, 2, 3]
+		(3 << 3) | 2, 0x03, 0x01, 0x02, 0x03,
+		// field 4: nested message { field 1: varint 1 }
+		(4 << 3) | 2, 0x02, (1 << 3), 0x01,
+		// field 4: nested message { field 1: varint 2 }
+		(4 << 3) | 2, 0x02, (1 << 3), 0x02,
+	}
+	def := NewDef(1, 2, 3, 5)
+	def.NestedTag(4, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	cases := []struct {
+		name     string
+		tag      int
+		expected int
+	}{
+		{name: "single value", tag: 1, expected: 1},
+		{name: "repeated value", tag: 2, expected: 3},
+		{name: "packed repeated value", tag: 3, expected: 1},
+		{name: "repeated nested message", tag: 4, expected: 2},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fd, err := res.FieldData(tc.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, fd.Count())
+		})
+	}
+	t.Run("nil field data", func(t *testing.T) {
+		var fd *FieldData
+		assert.Equal(t, 0, fd.Count())
+	})
+	t.Run("missing field", func(t *testing.T) {
+		assert.Equal(t, 0, res.m[5].Count())
+	})
+}
+
+func TestFieldDataRawBytes(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 1: varint 1
+		(1 << 3), 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: fixed32 1138
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 4: nested message { field 1: varint 1 }
+		(4 << 3) | 2, 0x02, (1 << 3), 0x01,
+	}
+	def := NewDef(1, 2, 3, -4)
+	def.NestedTag(4, 1)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	cases := []struct {
+		name     string
+		tag      int
+		expected [][]byte
+	}{
+		{name: "repeated varint", tag: 1, expected: [][]byte{{0x96, 0x01}, {0x01}}},
+		{name: "length-delimited", tag: 2, expected: [][]byte{[]byte("testing")}},
+		{name: "fixed32", tag: 3, expected: [][]byte{{0x72, 0x04, 0x00, 0x00}}},
+		{name: "raw nested message", tag: -4, expected: [][]byte{{(1 << 3), 0x01}}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fd, err := res.FieldData(tc.tag)
+			require.NoError(t, err)
+			got, err := fd.RawBytes()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+	t.Run("pass-through", func(t *testing.T) {
+		buf := make([]byte, len(sampleMessage))
+		enc := csproto.NewEncoder(buf)
+		for _, tag := range []int{1, 2, 3, -4} {
+			fd, err := res.FieldData(tag)
+			require.NoError(t, err)
+			raws, err := fd.RawBytes()
+			require.NoError(t, err)
+			for _, raw := range raws {
+				if tag < 0 {
+					tag = -tag
+				}
+				enc.EncodeRawField(tag, fd.WireType(), raw)
+			}
+		}
+		require.NoError(t, enc.Err())
+		assert.Equal(t, sampleMessage, buf)
+	})
+	t.Run("nested message", func(t *testing.T) {
+		fd, err := res.FieldData(4)
+		require.NoError(t, err)
+		got, err := fd.RawBytes()
+		assert.Nil(t, got)
+		assert.Error(t, err)
+	})
+	t.Run("nil field data", func(t *testing.T) {
+		var fd *FieldData
+		got, err := fd.RawBytes()
+		assert.Nil(t, got)
 		assert.ErrorIs(t, err, ErrTagNotFound)
 	})
 }
@@ -324,6 +2277,62 @@ func TestRawFieldData(t *testing.T) {
 	})
 }
 
+func TestZeroValuedFieldPresence(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint boolean false
+		(1 << 3), 0x00,
+		// field 2: varint int32 0
+		(2 << 3), 0x00,
+		// field 3: empty string
+		(3 << 3) | 2, 0x00,
+		// field 4: empty bytes
+		(4 << 3) | 2, 0x00,
+	}
+	def := NewDef(1, 2, 3, 4, 5)
+	res, err := Decode(sampleMessage, def)
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	t.Run("bool", func(t *testing.T) {
+		fd, err := res.FieldData(1)
+		require.NoError(t, err)
+		assert.True(t, fd.Has())
+		v, err := fd.BoolValue()
+		assert.NoError(t, err)
+		assert.False(t, v)
+	})
+	t.Run("int32", func(t *testing.T) {
+		fd, err := res.FieldData(2)
+		require.NoError(t, err)
+		assert.True(t, fd.Has())
+		v, err := fd.Int32Value()
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), v)
+	})
+	t.Run("string", func(t *testing.T) {
+		fd, err := res.FieldData(3)
+		require.NoError(t, err)
+		assert.True(t, fd.Has())
+		v, err := fd.StringValue()
+		assert.NoError(t, err)
+		assert.Equal(t, "", v)
+	})
+	t.Run("bytes", func(t *testing.T) {
+		fd, err := res.FieldData(4)
+		require.NoError(t, err)
+		assert.True(t, fd.Has())
+		v, err := fd.BytesValue()
+		assert.NoError(t, err)
+		assert.Empty(t, v)
+	})
+	t.Run("missing field", func(t *testing.T) {
+		fd, err := res.FieldData(5)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.False(t, fd.Has())
+	})
+}
+
 func TestBooleanFieldData(t *testing.T) {
 	var sampleMessage = []byte{
 		// field 1: varint boolean true
@@ -467,6 +2476,36 @@ func TestStringFieldData(t *testing.T) {
 	})
 }
 
+func TestStringFieldDataOutlivesInput(t *testing.T) {
+	t.Parallel()
+	data := []byte{
+		// field 1: single string - "test"
+		(1 << 3) | 2, 0x04, 0x74, 0x65, 0x73, 0x74,
+		// field 2: repeated string - "one", "two"
+		(2 << 3) | 2, 0x03, 0x6f, 0x6e, 0x65,
+		(2 << 3) | 2, 0x03, 0x74, 0x77, 0x6f,
+	}
+	res, err := Decode(data, NewDef(1, 2))
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	fd, err := res.FieldData(1)
+	require.NoError(t, err)
+	s, err := fd.StringValue()
+	require.NoError(t, err)
+	fd, err = res.FieldData(2)
+	require.NoError(t, err)
+	ss, err := fd.StringValues()
+	require.NoError(t, err)
+
+	// string values are copies so they must not change when the input data is modified
+	for i := range data {
+		data[i] = 0
+	}
+	assert.Equal(t, "test", s)
+	assert.Equal(t, []string{"one", "two"}, ss)
+}
+
 func TestBytesFieldData(t *testing.T) {
 	var sampleMessage = []byte{
 		// field 1: single bytes - [1,2,3,4]
@@ -697,6 +2736,10 @@ func TestInt32FieldData(t *testing.T) {
 		(8 << 3) | 5, 0x00, 0x01, 0x02, 0x03,
 		// field 9: varint int32 overflow (max int32 + 1)
 		(9 << 3), 0x80, 0x80, 0x80, 0x80, 0x08,
+		// field 10: packed repeated int32 - -42, min int32
+		(10 << 3) | 2, 0x14,
+		0xD6, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01,
+		0x80, 0x80, 0x80, 0x80, 0xF8, 0xFF, 0xFF, 0xFF, 0xFF, 0x01,
 	}
 	t.Parallel()
 	t.Run("zero", func(t *testing.T) {
@@ -803,6 +2846,27 @@ func TestInt32FieldData(t *testing.T) {
 			assert.Equal(t, expected, vs[i], "mismatched values at index %d", i)
 		}
 	})
+	t.Run("repeated negative int32", func(t *testing.T) {
+		t.Parallel()
+		def := NewDef(3, 10)
+		res, err := Decode(sampleMessage, def)
+		defer func() { _ = res.Close() }()
+		assert.NoError(t, err)
+
+		fd, err := res.FieldData(3)
+		assert.NoError(t, err)
+
+		vs, err := fd.Int32Values()
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{-42}, vs)
+
+		fd, err = res.FieldData(10)
+		assert.NoError(t, err)
+
+		vs, err = fd.Int32Values()
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{-42, math.MinInt32}, vs)
+	})
 	t.Run("tag not present", func(t *testing.T) {
 		t.Parallel()
 		def := NewDef(1)
@@ -1015,6 +3079,144 @@ func TestSInt32FieldData(t *testing.T) {
 	})
 }
 
+func TestEnumFieldData(t *testing.T) {
+	t.Parallel()
+	sampleMessage := make([]byte, 11+2+6+24+14+3)
+	enc := csproto.NewEncoder(sampleMessage)
+	// field 1: negative enum value
+	enc.EncodeInt32(1, -1)
+	// field 2: zero enum value
+	enc.EncodeInt32(2, 0)
+	// field 3: max int32 enum value
+	enc.EncodeInt32(3, math.MaxInt32)
+	// field 4: repeated enum values, not packed
+	for _, v := range []int32{-2, 1, math.MinInt32} {
+		enc.EncodeInt32(4, v)
+	}
+	// field 5: repeated enum values, packed
+	enc.EncodePackedInt32(5, []int32{0, 1, -3})
+	// field 6: string
+	enc.EncodeString(6, "a")
+
+	res, err := Decode(sampleMessage, NewDef(1, 2, 3, 4, 5, 6))
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	t.Run("negative value", func(t *testing.T) {
+		v, err := res.EnumValue(1)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(-1), v)
+	})
+	t.Run("zero value", func(t *testing.T) {
+		v, err := res.EnumValue(2)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), v)
+	})
+	t.Run("max int32 value", func(t *testing.T) {
+		v, err := res.EnumValue(3)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(math.MaxInt32), v)
+	})
+	t.Run("repeated values", func(t *testing.T) {
+		vs, err := res.EnumValues(4)
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{-2, 1, math.MinInt32}, vs)
+
+		// the last value is returned for a single value
+		v, err := res.EnumValue(4)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(math.MinInt32), v)
+	})
+	t.Run("packed repeated values", func(t *testing.T) {
+		vs, err := res.EnumValues(5)
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{0, 1, -3}, vs)
+	})
+	t.Run("single value as slice", func(t *testing.T) {
+		vs, err := res.EnumValues(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{-1}, vs)
+	})
+	t.Run("wire type mismatch", func(t *testing.T) {
+		_, err := res.EnumValue(6)
+		var wtErr *WireTypeMismatchError
+		assert.ErrorAs(t, err, &wtErr)
+	})
+	t.Run("tag not present", func(t *testing.T) {
+		_, err := res.EnumValue(7)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		_, err = res.EnumValues(7)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestDecodeResultTypedAccessors(t *testing.T) {
+	t.Parallel()
+	buf := make([]byte, 256)
+	enc := csproto.NewEncoder(buf)
+	enc.EncodeBool(1, true)
+	enc.EncodeString(2, "test")
+	enc.EncodeBytes(3, []byte{0x01, 0x02})
+	enc.EncodeUInt32(4, math.MaxUint32)
+	enc.EncodeInt32(5, math.MinInt32)
+	enc.EncodeSInt32(6, -42)
+	enc.EncodeUInt64(7, math.MaxUint64)
+	enc.EncodeInt64(8, math.MinInt64)
+	enc.EncodeSInt64(9, -42)
+	enc.EncodeFixed32(10, 42)
+	enc.EncodeFixed64(11, 42)
+	enc.EncodeFloat32(12, 3.14)
+	enc.EncodeFloat64(13, 3.14)
+	require.NoError(t, enc.Err())
+
+	res, err := Decode(buf[:enc.Written()], NewDef(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13))
+	require.NoError(t, err)
+	defer func() { _ = res.Close() }()
+
+	// each accessor should return the same value as the FieldData method and a single-element slice
+	// for the plural variant
+	cases := []struct {
+		name     string
+		tag      int
+		expected interface{}
+		single   func(tag int) (interface{}, error)
+		multiple func(tag int) (interface{}, error)
+	}{
+		{"bool", 1, true, func(tag int) (interface{}, error) { return res.BoolValue(tag) }, func(tag int) (interface{}, error) { return res.BoolValues(tag) }},
+		{"string", 2, "test", func(tag int) (interface{}, error) { return res.StringValue(tag) }, func(tag int) (interface{}, error) { return res.StringValues(tag) }},
+		{"bytes", 3, []byte{0x01, 0x02}, func(tag int) (interface{}, error) { return res.BytesValue(tag) }, func(tag int) (interface{}, error) { return res.BytesValues(tag) }},
+		{"uint32", 4, uint32(math.MaxUint32), func(tag int) (interface{}, error) { return res.UInt32Value(tag) }, func(tag int) (interface{}, error) { return res.UInt32Values(tag) }},
+		{"int32", 5, int32(math.MinInt32), func(tag int) (interface{}, error) { return res.Int32Value(tag) }, func(tag int) (interface{}, error) { return res.Int32Values(tag) }},
+		{"sint32", 6, int32(-42), func(tag int) (interface{}, error) { return res.SInt32Value(tag) }, func(tag int) (interface{}, error) { return res.SInt32Values(tag) }},
+		{"uint64", 7, uint64(math.MaxUint64), func(tag int) (interface{}, error) { return res.UInt64Value(tag) }, func(tag int) (interface{}, error) { return res.UInt64Values(tag) }},
+		{"int64", 8, int64(math.MinInt64), func(tag int) (interface{}, error) { return res.Int64Value(tag) }, func(tag int) (interface{}, error) { return res.Int64Values(tag) }},
+		{"sint64", 9, int64(-42), func(tag int) (interface{}, error) { return res.SInt64Value(tag) }, func(tag int) (interface{}, error) { return res.SInt64Values(tag) }},
+		{"fixed32", 10, uint32(42), func(tag int) (interface{}, error) { return res.Fixed32Value(tag) }, func(tag int) (interface{}, error) { return res.Fixed32Values(tag) }},
+		{"fixed64", 11, uint64(42), func(tag int) (interface{}, error) { return res.Fixed64Value(tag) }, func(tag int) (interface{}, error) { return res.Fixed64Values(tag) }},
+		{"float32", 12, float32(3.14), func(tag int) (interface{}, error) { return res.Float32Value(tag) }, func(tag int) (interface{}, error) { return res.Float32Values(tag) }},
+		{"float64", 13, float64(3.14), func(tag int) (interface{}, error) { return res.Float64Value(tag) }, func(tag int) (interface{}, error) { return res.Float64Values(tag) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := tc.single(tc.tag)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, v)
+
+			vs, err := tc.multiple(tc.tag)
+			assert.NoError(t, err)
+			rv := reflect.ValueOf(vs)
+			require.Equal(t, 1, rv.Len())
+			assert.Equal(t, tc.expected, rv.Index(0).Interface())
+
+			_, err = tc.single(99)
+			assert.ErrorIs(t, err, ErrTagNotFound)
+			_, err = tc.multiple(99)
+			assert.ErrorIs(t, err, ErrTagNotFound)
+		})
+	}
+}
+
 func TestUInt64FieldData(t *testing.T) {
 	var sampleMessage = []byte{
 		// field 1: min uint64 (0)
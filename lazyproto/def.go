@@ -5,6 +5,9 @@ import (
 	"math"
 
 	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/CrowdStrike/csproto"
 )
 
 // NewDef initializes and returns a new Def with mappings for the specified field tags.
@@ -47,6 +50,80 @@ func NewDef(tags ...int) Def {
 // [valid Protobuf field tags]: https://developers.google.com/protocol-buffers/docs/proto3#assigning_field_numbers
 type Def map[int]Def
 
+// DefFromFieldMask returns a new Def that decodes the fields selected by mask.
+//
+// A path that selects an entire nested message maps to the raw bytes of that field, which replaces any
+// mappings for fields within the nested message selected by other paths.
+func DefFromFieldMask(mask csproto.FieldMask) Def {
+	def := NewDef()
+	for _, path := range mask {
+		d := def
+		for i, tag := range path {
+			if i == len(path)-1 {
+				d[tag] = nil
+				break
+			}
+			nd, exists := d[tag]
+			if exists && nd == nil {
+				// the entire field has already been selected
+				break
+			}
+			if nd == nil {
+				nd = NewDef()
+				d[tag] = nd
+			}
+			d = nd
+		}
+	}
+	return def
+}
+
+// DefFromDescriptor returns a new Def that decodes all of the fields defined by desc.
+//
+// Message-typed fields, including map fields, are mapped to nested Def values generated from the
+// field's message descriptor, recursively, up to depth levels of nesting.  Beyond that, message-typed
+// fields map to the raw bytes of the field.  A depth of 0 generates a Def for only the top-level fields.
+//
+// An error is returned if desc is nil, if depth is negative, or if desc (or any nested message within
+// depth) contains a proto2 group field since groups are not supported by [Decode].
+func DefFromDescriptor(desc protoreflect.MessageDescriptor, depth int) (Def, error) {
+	if desc == nil {
+		return nil, fmt.Errorf("cannot generate a definition from a nil message descriptor")
+	}
+	if depth < 0 {
+		return nil, fmt.Errorf("invalid depth (%d), must be non-negative", depth)
+	}
+	return defFromDescriptor(desc, depth)
+}
+
+// defFromDescriptor is the internal implementation of [DefFromDescriptor], which assumes that the
+// parameters have already been validated.
+func defFromDescriptor(desc protoreflect.MessageDescriptor, depth int) (Def, error) {
+	fields := desc.Fields()
+	def := Def(make(map[int]Def, fields.Len()))
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		tag := int(fd.Number())
+		switch fd.Kind() {
+		case protoreflect.GroupKind:
+			return nil, fmt.Errorf("unsupported group field %s (tag=%d)", fd.FullName(), tag)
+		case protoreflect.MessageKind:
+			if depth == 0 {
+				def[tag] = nil
+				continue
+			}
+			nd, err := defFromDescriptor(fd.Message(), depth-1)
+			if err != nil {
+				return nil, err
+			}
+			def[tag] = nd
+		default:
+			def[tag] = nil
+		}
+	}
+	return def, nil
+}
+
 // Tags adds one or more field tags to the mapping, replacing any existing mappings, and returns the Def.
 func (d Def) Tags(tags ...int) Def {
 	for _, t := range tags {
@@ -63,12 +140,73 @@ func (d Def) NestedTag(tag int, nestedTags ...int) Def {
 	return nd
 }
 
+// defsEqual returns true if d1 and d2 contain the same mappings.  Nil and empty nested definitions are
+// considered equal since both result in the raw bytes of the field being decoded.
+func defsEqual(d1, d2 Def) bool {
+	if len(d1) != len(d2) {
+		return false
+	}
+	for tag, nd1 := range d1 {
+		nd2, ok := d2[tag]
+		if !ok || !defsEqual(nd1, nd2) {
+			return false
+		}
+	}
+	return true
+}
+
 // Get returns the mapping value for tag plus a boolean indicating whether or not the mapping existed
 func (d Def) Get(tag int) (Def, bool) {
 	v, ok := d[tag]
 	return v, ok
 }
 
+// Clone returns a deep copy of d, including any nested definitions, so that tags can be added to the
+// copy without modifying d.
+func (d Def) Clone() Def {
+	if d == nil {
+		return nil
+	}
+	res := Def(make(map[int]Def, len(d)))
+	for tag, nd := range d {
+		res[tag] = nd.Clone()
+	}
+	return res
+}
+
+// Merge returns a new Def that combines the mappings in d and other, neither of which is modified.
+// If a tag maps to a nested definition in both, the nested definitions are merged recursively.
+// Otherwise, the mapping in d takes precedence.
+//
+// An error is returned if either d or other is not valid.
+func (d Def) Merge(other Def) (Def, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	if err := other.Validate(); err != nil {
+		return nil, err
+	}
+	return d.merge(other), nil
+}
+
+// merge is the internal implementation of [Def.Merge], which assumes that d and other are valid.
+func (d Def) merge(other Def) Def {
+	res := d.Clone()
+	if res == nil {
+		res = NewDef()
+	}
+	for tag, ond := range other {
+		nd, exists := res[tag]
+		switch {
+		case !exists:
+			res[tag] = ond.Clone()
+		case nd != nil && ond != nil:
+			res[tag] = nd.merge(ond)
+		}
+	}
+	return res
+}
+
 // Validate checks that d is structurally and semantically valid and returns an error if it is not.
 func (d Def) Validate() error {
 	return d.validate()
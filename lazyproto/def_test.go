@@ -5,7 +5,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/CrowdStrike/csproto"
 )
@@ -259,3 +266,250 @@ func TestDefValidation(t *testing.T) {
 		})
 	})
 }
+
+func TestDefClone(t *testing.T) {
+	t.Parallel()
+	def := NewDef(1, -3)
+	def.NestedTag(3, 1).NestedTag(2, 1)
+
+	clone := def.Clone()
+	assert.Equal(t, def, clone)
+
+	// changes to the clone at any level should not affect the original
+	clone.Tags(2)
+	clone[3].Tags(4)
+	clone[3][2].Tags(5)
+	assert.Equal(t, Def{1: nil, -3: nil, 3: Def{1: nil, 2: Def{1: nil}}}, def)
+
+	assert.Nil(t, Def(nil).Clone())
+}
+
+func TestDefMerge(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		def      Def
+		other    Def
+		expected Def
+	}{
+		{
+			name:     "disjoint tags",
+			def:      NewDef(1, 2),
+			other:    NewDef(3),
+			expected: NewDef(1, 2, 3),
+		},
+		{
+			name:     "nil receiver",
+			def:      nil,
+			other:    NewDef(3),
+			expected: NewDef(3),
+		},
+		{
+			name:     "nil other",
+			def:      NewDef(1),
+			other:    nil,
+			expected: NewDef(1),
+		},
+		{
+			name:     "nested definitions are merged",
+			def:      Def{1: nil, 4: Def{1: nil, 2: Def{1: nil}}},
+			other:    Def{4: Def{2: Def{2: nil}, 3: nil}},
+			expected: Def{1: nil, 4: Def{1: nil, 2: Def{1: nil, 2: nil}, 3: nil}},
+		},
+		{
+			name:     "receiver takes precedence for raw field",
+			def:      Def{4: nil},
+			other:    Def{4: Def{1: nil}},
+			expected: Def{4: nil},
+		},
+		{
+			name:     "receiver takes precedence for nested definition",
+			def:      Def{4: Def{1: nil}},
+			other:    Def{4: nil},
+			expected: Def{4: Def{1: nil}},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defBefore, otherBefore := tc.def.Clone(), tc.other.Clone()
+			got, err := tc.def.Merge(tc.other)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+			// neither input should be modified
+			assert.Equal(t, defBefore, tc.def)
+			assert.Equal(t, otherBefore, tc.other)
+		})
+	}
+	t.Run("invalid definitions", func(t *testing.T) {
+		t.Parallel()
+		invalid := NewDef()
+		invalid.NestedTag(1, csproto.MaxTagValue+1)
+
+		_, err := invalid.Merge(NewDef(1))
+		assert.Error(t, err)
+		_, err = NewDef(1).Merge(invalid)
+		assert.Error(t, err)
+	})
+}
+
+func TestDefFromFieldMask(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		mask     csproto.FieldMask
+		expected Def
+	}{
+		{
+			name:     "empty mask",
+			mask:     nil,
+			expected: NewDef(),
+		},
+		{
+			name:     "top-level fields",
+			mask:     csproto.FieldMask{{1}, {3}},
+			expected: NewDef(1, 3),
+		},
+		{
+			name:     "nested fields",
+			mask:     csproto.FieldMask{{1}, {4, 1}, {4, 2}},
+			expected: Def{1: nil, 4: NewDef(1, 2)},
+		},
+		{
+			name:     "entire nested message after nested fields",
+			mask:     csproto.FieldMask{{4, 1}, {4}},
+			expected: NewDef(4),
+		},
+		{
+			name:     "entire nested message before nested fields",
+			mask:     csproto.FieldMask{{4}, {4, 1}},
+			expected: NewDef(4),
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			def := DefFromFieldMask(tc.mask)
+			assert.Equal(t, tc.expected, def)
+			assert.NoError(t, def.Validate())
+		})
+	}
+}
+
+func TestDefFromDescriptor(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		desc     protoreflect.MessageDescriptor
+		depth    int
+		expected Def
+	}{
+		{
+			name:     "scalar fields",
+			desc:     (&timestamppb.Timestamp{}).ProtoReflect().Descriptor(),
+			depth:    1,
+			expected: NewDef(1, 2),
+		},
+		{
+			name:     "message fields with depth 0",
+			desc:     (&structpb.Value{}).ProtoReflect().Descriptor(),
+			depth:    0,
+			expected: NewDef(1, 2, 3, 4, 5, 6),
+		},
+		{
+			name:  "message fields with depth 1",
+			desc:  (&structpb.Value{}).ProtoReflect().Descriptor(),
+			depth: 1,
+			expected: Def{
+				1: nil, 2: nil, 3: nil, 4: nil,
+				// Struct { map<string, Value> fields = 1 }
+				5: NewDef(1),
+				// ListValue { repeated Value values = 1 }
+				6: NewDef(1),
+			},
+		},
+		{
+			name:  "map fields",
+			desc:  (&structpb.Struct{}).ProtoReflect().Descriptor(),
+			depth: 2,
+			expected: Def{
+				// map entry { string key = 1; Value value = 2 }
+				1: Def{
+					1: nil,
+					2: NewDef(1, 2, 3, 4, 5, 6),
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			def, err := DefFromDescriptor(tc.desc, tc.depth)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, def)
+			assert.NoError(t, def.Validate())
+		})
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+		msg, _ := structpb.NewValue(map[string]interface{}{"name": "test"})
+		data, err := proto.Marshal(msg)
+		assert.NoError(t, err)
+		def, err := DefFromDescriptor(msg.ProtoReflect().Descriptor(), 3)
+		assert.NoError(t, err)
+
+		res, err := Decode(data, def)
+		assert.NoError(t, err)
+		defer func() { _ = res.Close() }()
+		// Value.struct_value -> Struct.fields -> entry.value -> Value.string_value
+		fd, err := res.FieldData(5, 1, 2, 3)
+		assert.NoError(t, err)
+		v, err := fd.StringValue()
+		assert.NoError(t, err)
+		assert.Equal(t, "test", v)
+	})
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+		_, err := DefFromDescriptor(nil, 1)
+		assert.Error(t, err)
+		_, err = DefFromDescriptor((&timestamppb.Timestamp{}).ProtoReflect().Descriptor(), -1)
+		assert.Error(t, err)
+	})
+	t.Run("group fields", func(t *testing.T) {
+		t.Parallel()
+		fdp := &descriptorpb.FileDescriptorProto{
+			Name:    proto.String("group_test.proto"),
+			Package: proto.String("lazyproto.test"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("WithGroup"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{
+							Name:     proto.String("thegroup"),
+							Number:   proto.Int32(1),
+							Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_GROUP.Enum(),
+							TypeName: proto.String(".lazyproto.test.WithGroup.TheGroup"),
+						},
+					},
+					NestedType: []*descriptorpb.DescriptorProto{
+						{Name: proto.String("TheGroup")},
+					},
+				},
+			},
+		}
+		fd, err := protodesc.NewFile(fdp, nil)
+		assert.NoError(t, err)
+		desc := fd.Messages().ByName("WithGroup")
+
+		_, err = DefFromDescriptor(desc, 1)
+		assert.Error(t, err)
+		// the group is still a "message" field at depth 0
+		_, err = DefFromDescriptor(desc, 0)
+		assert.Error(t, err)
+	})
+}
@@ -33,6 +33,11 @@ import (
 // A zero-valued instance is the equivalent of a varint field with no data. All methods valid for varint
 // data will return [ErrTagNotFound] and all others will return a [WireTypeMismatchError].
 //
+// Fields that are present in the message data are always recorded, even if the encoded value is the
+// zero value for its type (0, false, "", or an empty byte slice).  This means that [FieldData.Has] can
+// be used to check for the presence of proto2 and proto3 optional fields, which are encoded whenever
+// they are set, regardless of their value.
+//
 // To avoid panics, any method called on a nil instance returns a zero value and [ErrTagNotFound].
 type FieldData struct {
 	// holds the Protobuf wire type from the source data
@@ -44,6 +49,82 @@ type FieldData struct {
 	data []any
 }
 
+// Has returns true if the field was present in the decoded message data, even if the value was the
+// zero value for its type.
+func (fd *FieldData) Has() bool {
+	return fd != nil && len(fd.data) > 0
+}
+
+// Count returns the number of times the field occurred in the decoded message data without converting
+// the values.  For non-repeated fields this is 0 or 1.  Packed repeated fields are counted once for each
+// packed chunk, so Count returns 1 unless the field appeared more than once in the message, and the
+// number of elements is only known after calling one of the typed *Values() accessors.
+//
+// Count returns 0 if fd is nil.
+func (fd *FieldData) Count() int {
+	if fd == nil {
+		return 0
+	}
+	return len(fd.data)
+}
+
+// WireType returns the Protobuf wire type of the field in the decoded message data, which can be used
+// to determine which typed accessors are valid for fd.  Nested messages, strings, bytes, and packed
+// repeated fields are all [csproto.WireTypeLengthDelimited].
+//
+// WireType returns -1, which is not a valid wire type, if fd is nil.
+func (fd *FieldData) WireType() csproto.WireType {
+	if fd == nil {
+		return -1
+	}
+	return fd.wt
+}
+
+// fieldDataStringMaxBytes is the maximum number of bytes of a length-delimited value that are included
+// in the output of [FieldData.String] and [DecodeResult.DebugString].
+const fieldDataStringMaxBytes = 16
+
+// fieldDataStringLayout is the format used by FieldData.String()
+var fieldDataStringLayout = fieldDataLayout{writeValue: writeFieldDataRawValue}
+
+// writeFieldDataRawValue writes varint values in hex and decimal, length-delimited values as the length
+// followed by up to the first fieldDataStringMaxBytes bytes in hex, and other values as the raw bytes.
+func writeFieldDataRawValue(sb *strings.Builder, wt csproto.WireType, v []byte) {
+	switch wt {
+	case csproto.WireTypeVarint:
+		if n, _, err := csproto.DecodeVarint(v); err == nil {
+			fmt.Fprintf(sb, "%#x (%d)", n, n)
+			return
+		}
+	case csproto.WireTypeLengthDelimited:
+		writeDebugRawValue(sb, wt, v)
+		return
+	default:
+		// fall through and write the raw bytes
+	}
+	fmt.Fprintf(sb, "[% x]", v)
+}
+
+// String returns a diagnostic representation of the wire type and raw values held by fd.
+//
+// Varint values are shown in both hex and decimal, length-delimited values are shown as the length
+// followed by up to the first 16 bytes in hex, and fixed-width values are shown as the raw bytes.
+//
+//	varint: 0x96 (150), 0x1 (1)
+//	length-delimited: len=7 [74 65 73 74 69 6e 67]
+//	fixed32: [72 04 00 00]
+//
+// Nested messages are written inline inside of braces, using the same format for their values.
+func (fd *FieldData) String() string {
+	if fd == nil {
+		return "<nil>"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: ", fd.wt)
+	fieldDataStringLayout.writeFieldValues(&sb, fd, 0)
+	return sb.String()
+}
+
 // BoolValue converts the lazily-decoded field data into a bool.
 //
 // Since Protobuf encodes boolean values as integers, any varint-encoded integer value is valid. A value
@@ -112,6 +193,36 @@ func (fd *FieldData) BytesValues() ([][]byte, error) {
 	})
 }
 
+// RawBytes returns the encoded value bytes for each occurrence of the field, without the tag key or, for
+// length-delimited fields, the length prefix, and without any type conversion.  Varint and fixed-width
+// values are returned exactly as they were encoded, so they can be passed to
+// [csproto.Encoder.EncodeRawField] along with the tag and WireType() to forward the field verbatim.
+//
+// The returned slice is newly allocated but the byte slices it contains are owned by fd and must not
+// be modified.  An error is returned if the field was decoded as a nested message.
+//
+// See the [FieldData] docs for more specific details about interpreting lazily-decoded data.
+func (fd *FieldData) RawBytes() ([][]byte, error) {
+	if fd == nil || len(fd.data) == 0 {
+		return nil, ErrTagNotFound
+	}
+	res := make([][]byte, 0, len(fd.data))
+	for _, rv := range fd.data {
+		switch data := rv.(type) {
+		case []byte:
+			res = append(res, data)
+		case map[int]*FieldData:
+			return nil, fmt.Errorf("cannot return raw bytes for a nested message")
+		default:
+			// TODO: should this be a panic?
+			// . elements of fd.data *SHOULD* always contain either []byte or map[int]*FieldData so this
+			//   is a "just in case" path
+			return nil, rawValueConversionError[[]byte](data)
+		}
+	}
+	return res, nil
+}
+
 // UInt32Value converts the lazily-decoded field data into a uint32.
 //
 // See the [FieldData] docs for more specific details about interpreting lazily-decoded data.
@@ -183,13 +294,45 @@ func (fd *FieldData) Int32Values() ([]int32, error) {
 			return 0, 0, err
 		}
 		// ensure the result is within [-math.MaxInt32, math.MaxInt32] when converted to a signed value
-		if value > math.MaxUint32 {
+		if i64 := int64(value); i64 > math.MaxInt32 || i64 < math.MinInt32 {
 			return 0, 0, csproto.ErrValueOverflow
 		}
 		return int32(value), n, nil
 	})
 }
 
+// EnumValue converts the lazily-decoded field data into an int32 enum value.
+//
+// Protobuf encodes enum values the same as int32, but negative values are sign-extended to 64 bits, so
+// unlike Int32Value() the decoded value is truncated to 32 bits rather than being checked for overflow.
+//
+// See the [FieldData] docs for more specific details about interpreting lazily-decoded data.
+func (fd *FieldData) EnumValue() (int32, error) {
+	return scalarValue(fd, csproto.WireTypeVarint, func(data []byte) (int32, error) {
+		value, _, err := csproto.DecodeVarint(data)
+		if err != nil {
+			return 0, err
+		}
+		return int32(int64(value)), nil
+	})
+}
+
+// EnumValues converts the lazily-decoded field data into a []int32 of enum values.
+//
+// Protobuf encodes enum values the same as int32, but negative values are sign-extended to 64 bits, so
+// unlike Int32Values() the decoded values are truncated to 32 bits rather than being checked for overflow.
+//
+// See the [FieldData] docs for more specific details about interpreting lazily-decoded data.
+func (fd *FieldData) EnumValues() ([]int32, error) {
+	return sliceValue(fd, csproto.WireTypeVarint, func(data []byte) (int32, int, error) {
+		value, n, err := csproto.DecodeVarint(data)
+		if err != nil {
+			return 0, 0, err
+		}
+		return int32(int64(value)), n, nil
+	})
+}
+
 // SInt32Value converts the lazily-decoded field data into an int32.
 //
 // Use this method to retreive values that are defined as sint32 in the Protobuf message. Fields that
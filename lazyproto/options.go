@@ -0,0 +1,194 @@
+package lazyproto
+
+import (
+	"context"
+	"time"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+// DecoderOption defines a functional option for customizing the behavior of [Decode] and [DecodeMany].
+type DecoderOption func(*decodeOptions)
+
+// WithFilterFunc returns a decoder option that narrows the fields extracted by [Decode] at runtime.
+// A top-level tag is only decoded if it is present in the [Def] and fn(tag) returns true, which allows
+// a broad [Def] to be built once at startup and then restricted per call.  Fields that are excluded by
+// fn are skipped as if they were not in the [Def].
+//
+// fn is only called for top-level tags that are in the [Def].  The tags of nested messages are not
+// filtered.
+func WithFilterFunc(fn func(tag int) bool) DecoderOption {
+	return func(opts *decodeOptions) {
+		opts.filter = fn
+	}
+}
+
+// WithErrorHandler returns a decoder option that allows [Decode] to continue past invalid fields, which
+// is useful for best-effort decoding of data where some fields may be corrupted.
+//
+// fn is called with the tag, wire type, and error for each field that cannot be decoded, including
+// fields in nested messages.  If fn returns true, the offending field is skipped and decoding continues
+// with the next field.  If fn returns false, decoding stops and the error is returned.  If the end of the
+// invalid field cannot be determined, such as when the tag or length prefix is malformed, the remaining
+// data cannot be decoded and the fields decoded so far are returned.
+//
+// If no handler is set, [Decode] returns on the first error.
+func WithErrorHandler(fn func(tag int, wt csproto.WireType, err error) bool) DecoderOption {
+	return func(opts *decodeOptions) {
+		opts.errorHandler = fn
+	}
+}
+
+// WithMaxDepth returns a decoder option that limits how deeply nested messages can be decoded, which
+// bounds the recursion used to decode pathologically deep data when the [Def] is also deep, such as one
+// generated by [DefFromDescriptor] for a recursive message type.  The top-level message is at depth 0
+// and each nested message is one level deeper than its parent.
+//
+// If the data contains a nested message deeper than n that is selected by the [Def], decoding fails with
+// an error that wraps [ErrRecursionLimitExceeded].  A value of zero or less disables the limit.
+func WithMaxDepth(n int) DecoderOption {
+	return func(opts *decodeOptions) {
+		opts.maxDepth = n
+	}
+}
+
+// WithMaxNestedMessageSize returns a decoder option that limits the size, in bytes, of the nested messages
+// that are decoded recursively, which guards against adversarial data that declares very large nested
+// messages in order to exhaust the resources of the decoder.  The limit is only applied to fields that
+// map to a nested [Def].  Other length-delimited fields are still bounded by the size of the data.
+//
+// If a nested message selected by the [Def] is larger than n bytes, decoding fails with an error that
+// wraps [ErrNestedMessageTooLarge].  A value of zero or less disables the limit.
+func WithMaxNestedMessageSize(n int) DecoderOption {
+	return func(opts *decodeOptions) {
+		opts.maxNestedSize = n
+	}
+}
+
+// WithTagLimit returns a decoder option that limits the number of values that are captured for the
+// specified top-level tag to max, which bounds the memory used for repeated fields with a very large
+// number of occurrences.  Once max values have been captured, any subsequent occurrences of the field
+// are skipped silently.  Each chunk of a packed repeated field counts as a single occurrence.  The limit
+// also applies to values captured as raw bytes using a negative tag in the [Def], so both the parsed and
+// raw values for tag are skipped once max occurrences have been captured.
+//
+// Limits are tracked per tag, so WithTagLimit can be passed multiple times to set different limits for
+// different tags.  A max of zero or less removes the limit for tag.
+func WithTagLimit(tag int, max int) DecoderOption {
+	return func(opts *decodeOptions) {
+		if max <= 0 {
+			delete(opts.tagLimits, tag)
+			return
+		}
+		if opts.tagLimits == nil {
+			opts.tagLimits = make(map[int]int)
+		}
+		opts.tagLimits[tag] = max
+	}
+}
+
+// WithCloseHook returns a decoder option that registers fn to be called when a [DecodeResult] returned
+// by [Decode], [DecodeContext], or [DecodeMany] is closed, which can be used to record telemetry such as
+// the time between decoding a message and releasing the result.  fn is called after the field data in
+// the result has been cleared but before its internal resources are returned to the pool, and must not
+// modify the result.
+//
+// WithCloseHook can be passed multiple times to register multiple hooks, which are called in the order
+// that they were registered.  Hooks are only called once, even if Close() is called more than once.
+func WithCloseHook(fn func(*DecodeResult)) DecoderOption {
+	return func(opts *decodeOptions) {
+		if fn != nil {
+			opts.closeHooks = append(opts.closeHooks, fn)
+		}
+	}
+}
+
+// WithDecodeObserver returns a decoder option that calls fn after each message is successfully decoded
+// with the elapsed time and the size of the message data, which allows decode latency to be recorded in
+// a metrics system, such as a histogram, without modifying the call site.  fn is called once for each
+// message decoded by [Decode], [DecodeContext], [DecodeMany], or [DecodeResult.DecodeInto], but is not
+// called for empty data or an empty [Def] since there is nothing to decode.
+//
+// fn is called synchronously, so it should return quickly.
+func WithDecodeObserver(fn func(dur time.Duration, messageBytes int)) DecoderOption {
+	return func(opts *decodeOptions) {
+		opts.observer = fn
+	}
+}
+
+// WithUnknownFields returns a decoder option that retains the raw values of the top-level fields that are
+// not in the [Def], which can then be retrieved using [DecodeResult.UnknownFields].  This allows
+// message-forwarding proxies to preserve fields that they do not otherwise inspect.
+//
+// By default, fields that are not in the [Def] are skipped without being retained.
+func WithUnknownFields() DecoderOption {
+	return func(opts *decodeOptions) {
+		opts.keepUnknown = true
+	}
+}
+
+// decodeOptions holds the options that customize the behavior of the decoder
+//
+// The zero value decodes all fields in the [Def].
+type decodeOptions struct {
+	// If set, only top-level tags for which filter returns true are decoded
+	filter func(tag int) bool
+	// If set, called for fields that cannot be decoded to determine whether or not to continue
+	errorHandler func(tag int, wt csproto.WireType, err error) bool
+	// If greater than zero, the maximum depth of nested messages
+	maxDepth int
+	// If greater than zero, the maximum size of nested messages
+	maxNestedSize int
+	// If set, the maximum number of values to capture for each top-level tag
+	tagLimits map[int]int
+	// If set, functions to call when a top-level result is closed
+	closeHooks []func(*DecodeResult)
+	// If set, called with the elapsed time and data size after each top-level message is decoded
+	observer func(dur time.Duration, messageBytes int)
+	// If true, the top-level fields that are not in the def are retained
+	keepUnknown bool
+	// If set, decoding stops when the context is cancelled
+	//
+	// This is not exposed as an option since it is set by DecodeContext().
+	ctx context.Context
+}
+
+// shouldDecode returns true if data needs to be decoded, which is the case when there is data and
+// either the def is not empty or unknown fields are being retained.
+func (o *decodeOptions) shouldDecode(data []byte, def Def) bool {
+	return len(data) > 0 && (len(def) > 0 || o.keepUnknown)
+}
+
+// limitReached returns true if a limit was set for tag using WithTagLimit() and n values have already
+// been captured.
+func (o *decodeOptions) limitReached(tag int, n int) bool {
+	max, ok := o.tagLimits[tag]
+	return ok && n >= max
+}
+
+// startObserving returns the start time for a call to the observer set using WithDecodeObserver(), if
+// any, when decoding the top-level message.  Otherwise, it returns the zero time to avoid the overhead of
+// reading the clock.
+func (o *decodeOptions) startObserving(path []int) time.Time {
+	if o.observer == nil || len(path) > 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observe calls the observer set using WithDecodeObserver(), if any, with the time elapsed since start
+// and the size of the decoded data.
+func (o *decodeOptions) observe(start time.Time, n int) {
+	if o.observer != nil {
+		o.observer(time.Since(start), n)
+	}
+}
+
+// newDecodeOptions returns a decodeOptions instance with all of the provided options applied
+func newDecodeOptions(opts []DecoderOption) *decodeOptions {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
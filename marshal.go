@@ -2,8 +2,11 @@ package csproto
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
 )
 
 var (
@@ -15,6 +18,18 @@ var (
 	ErrUnmarshaler = errors.New("message does not implement csproto.Unmarshaler")
 )
 
+// RequiredFieldMissingError is returned by the generated Unmarshal() method of Proto2 messages when
+// one or more required fields are not present in the decoded data.
+type RequiredFieldMissingError struct {
+	// Fields contains the names of the missing fields
+	Fields []string
+}
+
+// Error satisfies the error interface
+func (e *RequiredFieldMissingError) Error() string {
+	return "cannot unmarshal, one or more required fields missing: " + strings.Join(e.Fields, ",")
+}
+
 // ProtoV1Sizer defines the interface for a type that provides custom Protobuf V1 sizing logic.
 type ProtoV1Sizer interface {
 	XXX_Size() int
@@ -60,9 +75,41 @@ type Unmarshaler interface {
 	Unmarshal([]byte) error
 }
 
+// MarshalOption defines a function that sets a specific marshaling option
+type MarshalOption func(*marshalOptions)
+
+// WithFieldMask returns a MarshalOption that restricts the marshaled data to the fields selected by
+// mask.  The message passed to Marshal() is not modified.
+//
+// Field masks require Protobuf reflection so they are only supported for messages generated by
+// Google's V1 and V2 Protobuf tools.  An empty mask is ignored.
+func WithFieldMask(mask FieldMask) MarshalOption {
+	return func(opts *marshalOptions) {
+		opts.fieldMask = mask
+	}
+}
+
+// marshalOptions defines the binary marshaling options
+type marshalOptions struct {
+	// fieldMask restricts marshaling to the selected fields
+	fieldMask FieldMask
+}
+
 // Marshal marshals msg to binary Protobuf format, delegating to the appropriate underlying
 // Protobuf API based on the concrete type of msg.
-func Marshal(msg interface{}) ([]byte, error) {
+func Marshal(msg interface{}, opts ...MarshalOption) ([]byte, error) {
+	var mo marshalOptions
+	for _, o := range opts {
+		o(&mo)
+	}
+	if len(mo.fieldMask) > 0 {
+		masked, err := applyFieldMask(msg, mo.fieldMask)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(masked)
+	}
+
 	if pm, ok := msg.(Marshaler); ok {
 		return pm.Marshal()
 	}
@@ -80,6 +127,46 @@ func Marshal(msg interface{}) ([]byte, error) {
 	return nil, ErrMarshaler
 }
 
+// CanMarshal returns true if msg is a non-nil message that can be passed to Marshal().
+func CanMarshal(msg interface{}) bool {
+	if isNilMessage(msg) {
+		return false
+	}
+	switch msg.(type) {
+	case Marshaler, ProtoV1Marshaler, proto.Message, protoadapt.MessageV1:
+		return true
+	default:
+		return false
+	}
+}
+
+// AsProtoMessage returns msg as a Google V2 [proto.Message] and a boolean value indicating whether or
+// not the conversion succeeded.  Google V1 and Gogo messages are wrapped using [protoadapt.MessageV2Of].
+//
+// The returned bool is false if msg is nil, including a typed nil pointer, or is not a Protobuf message.
+func AsProtoMessage(msg interface{}) (proto.Message, bool) {
+	if isNilMessage(msg) {
+		return nil, false
+	}
+	switch tv := msg.(type) {
+	case proto.Message:
+		return tv, true
+	case protoadapt.MessageV1:
+		return protoadapt.MessageV2Of(tv), true
+	default:
+		return nil, false
+	}
+}
+
+// isNilMessage returns true if msg is nil or is a typed nil pointer
+func isNilMessage(msg interface{}) bool {
+	if msg == nil {
+		return true
+	}
+	v := reflect.ValueOf(msg)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
 // Unmarshal decodes the specified Protobuf data into msg, delegating to the appropriate underlying
 // Protobuf API based on the concrete type of msg.
 func Unmarshal(data []byte, msg interface{}) error {
@@ -1,11 +1,14 @@
 package csproto
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
 
 	gogo "github.com/gogo/protobuf/proto"
 	google "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // MessageType defines the types of Protobuf message implementations this API supports.
@@ -54,3 +57,41 @@ func deduceMsgType(msg interface{}, typ reflect.Type) MessageType {
 	}
 	return MessageTypeGoogleV1
 }
+
+// MessageName returns the fully-qualified Protobuf name of msg, ex: "mypackage.MyMessage".
+//
+// An error is returned if msg is nil or if the name cannot be determined, such as when msg is not a
+// Protobuf message or its type has not been registered with the corresponding Protobuf runtime.
+func MessageName(msg interface{}) (protoreflect.FullName, error) {
+	if isNilMessage(msg) {
+		return "", fmt.Errorf("unable to determine the message name of a nil message")
+	}
+	var name protoreflect.FullName
+	switch MsgType(msg) {
+	case MessageTypeGoogle:
+		name = google.MessageName(msg.(google.Message))
+	case MessageTypeGoogleV1:
+		if m, ok := msg.(protoadapt.MessageV1); ok {
+			name = protoadapt.MessageV2Of(m).ProtoReflect().Descriptor().FullName()
+		}
+	case MessageTypeGogo:
+		name = protoreflect.FullName(gogo.MessageName(msg.(gogo.Message)))
+	default:
+		// leave name empty, which is reported below
+	}
+	if name == "" {
+		return "", fmt.Errorf("unable to determine the message name for type %T", msg)
+	}
+	return name, nil
+}
+
+// MessageShortName returns the unqualified Protobuf name of msg, ex: "MyMessage".
+//
+// See [MessageName] for details on when an error is returned.
+func MessageShortName(msg interface{}) (protoreflect.Name, error) {
+	name, err := MessageName(msg)
+	if err != nil {
+		return "", err
+	}
+	return name.Name(), nil
+}
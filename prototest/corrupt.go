@@ -0,0 +1,95 @@
+package prototest
+
+import (
+	"github.com/CrowdStrike/csproto"
+)
+
+// CorruptionStrategy defines a function that corrupts encoded Protobuf message data for use in negative
+// tests.  Implementations may modify the provided slice in place and return it, or return a new slice.
+type CorruptionStrategy func(data []byte) []byte
+
+// CorruptMessage returns a corrupted copy of data by applying strategy to it.  The original data is
+// not modified.
+//
+// This function is provided to make it easier to generate invalid messages for testing decoder error
+// handling.
+func CorruptMessage(data []byte, strategy CorruptionStrategy) []byte {
+	d := make([]byte, len(data))
+	copy(d, data)
+	return strategy(d)
+}
+
+// TruncateAt returns a CorruptionStrategy that cuts the data after n bytes.  The data is returned
+// unchanged if it is not longer than n bytes.
+func TruncateAt(n int) CorruptionStrategy {
+	return func(data []byte) []byte {
+		if n < 0 || n >= len(data) {
+			return data
+		}
+		return data[:n]
+	}
+}
+
+// FlipBit returns a CorruptionStrategy that flips bit bitIdx (0-7, where 0 is the least significant bit)
+// of the byte at byteIdx.  The data is returned unchanged if either index is out of range.
+func FlipBit(byteIdx, bitIdx int) CorruptionStrategy {
+	return func(data []byte) []byte {
+		if byteIdx < 0 || byteIdx >= len(data) || bitIdx < 0 || bitIdx > 7 {
+			return data
+		}
+		data[byteIdx] ^= 1 << bitIdx
+		return data
+	}
+}
+
+// InsertZeros returns a CorruptionStrategy that inserts n zero bytes into the middle of the data.
+func InsertZeros(n int) CorruptionStrategy {
+	return func(data []byte) []byte {
+		if n <= 0 {
+			return data
+		}
+		mid := len(data) / 2
+		res := make([]byte, len(data)+n)
+		copy(res, data[:mid])
+		copy(res[mid+n:], data[mid:])
+		return res
+	}
+}
+
+// CorruptVarintLength returns a CorruptionStrategy that replaces the length prefix of a length-delimited
+// field with the varint encoding of the maximum uint64 value.  The tagIdx parameter is the zero-based
+// index of the target field among the length-delimited fields at the top level of the message.
+//
+// The data is returned unchanged if it contains fewer than tagIdx+1 length-delimited fields or if it
+// cannot be parsed up to the target field.
+func CorruptVarintLength(tagIdx int) CorruptionStrategy {
+	return func(data []byte) []byte {
+		dec := csproto.NewDecoder(data)
+		for idx := 0; dec.More(); {
+			tag, wt, err := dec.DecodeTag()
+			if err != nil {
+				return data
+			}
+			if wt == csproto.WireTypeLengthDelimited {
+				if idx == tagIdx {
+					start := dec.Offset()
+					_, n, err := csproto.DecodeVarint(data[start:])
+					if err != nil {
+						return data
+					}
+					maxLen := make([]byte, csproto.SizeOfVarint(^uint64(0)))
+					csproto.EncodeVarint(maxLen, ^uint64(0))
+					res := make([]byte, 0, len(data)-n+len(maxLen))
+					res = append(res, data[:start]...)
+					res = append(res, maxLen...)
+					return append(res, data[start+n:]...)
+				}
+				idx++
+			}
+			if _, err := dec.Skip(tag, wt); err != nil {
+				return data
+			}
+		}
+		return data
+	}
+}
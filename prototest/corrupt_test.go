@@ -0,0 +1,92 @@
+package prototest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/prototest"
+)
+
+func TestCorruptMessage(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: fixed32 1138
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		// field 4: bytes {0x01, 0x02}
+		(4 << 3) | 2, 0x02, 0x01, 0x02,
+	}
+	cases := []struct {
+		name     string
+		strategy prototest.CorruptionStrategy
+		expected []byte
+	}{
+		{
+			name:     "truncate",
+			strategy: prototest.TruncateAt(5),
+			expected: sampleMessage[:5],
+		},
+		{
+			name:     "flip bit",
+			strategy: prototest.FlipBit(2, 7),
+			expected: append(append([]byte{}, sampleMessage[:2]...), append([]byte{0x81}, sampleMessage[3:]...)...),
+		},
+		{
+			name:     "insert zeros",
+			strategy: prototest.InsertZeros(2),
+			expected: append(append(append([]byte{}, sampleMessage[:10]...), 0x00, 0x00), sampleMessage[10:]...),
+		},
+		{
+			name:     "corrupt varint length",
+			strategy: prototest.CorruptVarintLength(1),
+			expected: append(append(append([]byte{}, sampleMessage[:18]...), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01), sampleMessage[19:]...),
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			original := append([]byte{}, sampleMessage...)
+
+			got := prototest.CorruptMessage(sampleMessage, tc.strategy)
+
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, original, sampleMessage, "the source data should not be modified")
+			assert.NotPanics(t, func() {
+				assert.Error(t, decodeAll(got), "decoding corrupted data should fail")
+			})
+		})
+	}
+	t.Run("out of range parameters", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []prototest.CorruptionStrategy{
+			prototest.TruncateAt(len(sampleMessage)),
+			prototest.FlipBit(len(sampleMessage), 0),
+			prototest.FlipBit(0, 8),
+			prototest.InsertZeros(0),
+			prototest.CorruptVarintLength(2),
+		} {
+			assert.Equal(t, sampleMessage, prototest.CorruptMessage(sampleMessage, s))
+		}
+	})
+}
+
+// decodeAll reads all of the fields in data and returns the first error
+func decodeAll(data []byte) error {
+	dec := csproto.NewDecoder(data)
+	for dec.More() {
+		tag, wt, err := dec.DecodeTag()
+		if err != nil {
+			return err
+		}
+		if _, err := dec.Skip(tag, wt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
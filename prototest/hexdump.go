@@ -0,0 +1,80 @@
+package prototest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const hexDumpBytesPerLine = 16
+
+// HexDump returns a human-readable representation of data, similar to the output of xxd or hexdump -C,
+// that is more useful than the default formatting of a []byte in test failure messages.
+//
+// Each line contains the offset of the first byte, up to 16 bytes in hex, then the printable ASCII
+// characters for those bytes, with '.' for non-printable characters.
+//
+//	00000000  08 96 01 12 07 74 65 73  74 69 6e 67              |.....testing|
+func HexDump(data []byte) string {
+	return HexDumpAnnotated(data, nil)
+}
+
+// HexDumpAnnotated returns the same output as HexDump() with additional comment lines for the byte
+// offsets in annotations.  Each comment is written on the line following the bytes it describes with
+// a marker pointing to the annotated byte.
+//
+//	00000000  08 96 01 12 07 74 65 73  74 69 6e 67              |.....testing|
+//	          ^ tag=1, varint
+//	                   ^ tag=2, length-delimited
+//
+// Annotations for offsets that are outside of data are ignored.
+func HexDumpAnnotated(data []byte, annotations map[int]string) string {
+	offsets := make([]int, 0, len(annotations))
+	for off := range annotations {
+		if off >= 0 && off < len(data) {
+			offsets = append(offsets, off)
+		}
+	}
+	sort.Ints(offsets)
+
+	var sb strings.Builder
+	for start := 0; start < len(data); start += hexDumpBytesPerLine {
+		end := start + hexDumpBytesPerLine
+		if end > len(data) {
+			end = len(data)
+		}
+		line := data[start:end]
+
+		fmt.Fprintf(&sb, "%08x  ", start)
+		for i := 0; i < hexDumpBytesPerLine; i++ {
+			if i == hexDumpBytesPerLine/2 {
+				sb.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(&sb, "%02x ", line[i])
+			} else {
+				sb.WriteString("   ")
+			}
+		}
+		sb.WriteString(" |")
+		for _, b := range line {
+			if b >= 0x20 && b <= 0x7e {
+				sb.WriteByte(b)
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteString("|\n")
+
+		for len(offsets) > 0 && offsets[0] < end {
+			i := offsets[0] - start
+			col := 10 + 3*i
+			if i >= hexDumpBytesPerLine/2 {
+				col++
+			}
+			fmt.Fprintf(&sb, "%s^ %s\n", strings.Repeat(" ", col), annotations[offsets[0]])
+			offsets = offsets[1:]
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package prototest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CrowdStrike/csproto/prototest"
+)
+
+func TestHexDump(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "empty",
+			data:     nil,
+			expected: "",
+		},
+		{
+			name: "partial line",
+			data: []byte{0x08, 0x96, 0x01, 0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'},
+			expected: "" +
+				"00000000  08 96 01 12 07 74 65 73  74 69 6e 67              |.....testing|\n",
+		},
+		{
+			name: "multiple lines",
+			data: []byte("0123456789abcdef\x00\xff"),
+			expected: "" +
+				"00000000  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|\n" +
+				"00000010  00 ff                                             |..|\n",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, prototest.HexDump(tc.data))
+		})
+	}
+}
+
+func TestHexDumpAnnotated(t *testing.T) {
+	t.Parallel()
+	data := []byte("0123456789abcdef\x00\xff")
+	annotations := map[int]string{
+		17: "second line",
+		0:  "first byte",
+		9:  "after the gap",
+		99: "out of range",
+	}
+	expected := "" +
+		"00000000  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|\n" +
+		"          ^ first byte\n" +
+		"                                      ^ after the gap\n" +
+		"00000010  00 ff                                             |..|\n" +
+		"             ^ second line\n"
+
+	assert.Equal(t, expected, prototest.HexDumpAnnotated(data, annotations))
+}
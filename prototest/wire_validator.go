@@ -0,0 +1,148 @@
+package prototest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+// WireValidator asserts that encoded Protobuf message data satisfies a set of wire format invariants,
+// which makes tests of encoded data less brittle than comparing against an exact sequence of bytes.
+//
+// Assertions are registered by calling the builder methods, which can be chained, and are then checked
+// against the encoded data by Validate().  The zero value is ready to use and, with no assertions
+// registered, only checks that the data can be parsed.
+//
+//	var v prototest.WireValidator
+//	v.HasTag(1, csproto.WireTypeVarint).HasTagCount(2, 3).TagsInAscendingOrder()
+//	v.Validate(t, data)
+type WireValidator struct {
+	checks []wireCheck
+}
+
+// wireCheck validates the fields scanned from the encoded data and returns a non-empty failure message
+// if the check does not pass.
+type wireCheck func(fields []wireField) string
+
+// wireField holds the tag and wire type of a single field in the encoded data.
+type wireField struct {
+	offset int
+	tag    int
+	wt     csproto.WireType
+}
+
+// HasTag adds an assertion that the data contains at least one field with the specified tag and wire
+// type.
+func (v *WireValidator) HasTag(tag int, wt csproto.WireType) *WireValidator {
+	v.checks = append(v.checks, func(fields []wireField) string {
+		for _, f := range fields {
+			if f.tag == tag && f.wt == wt {
+				return ""
+			}
+		}
+		return fmt.Sprintf("expected a field with tag %d and wire type %s", tag, wt)
+	})
+	return v
+}
+
+// HasTagCount adds an assertion that the data contains exactly n fields with the specified tag.
+func (v *WireValidator) HasTagCount(tag int, n int) *WireValidator {
+	v.checks = append(v.checks, func(fields []wireField) string {
+		count := 0
+		for _, f := range fields {
+			if f.tag == tag {
+				count++
+			}
+		}
+		if count != n {
+			return fmt.Sprintf("expected %d fields with tag %d, found %d", n, tag, count)
+		}
+		return ""
+	})
+	return v
+}
+
+// TagsInAscendingOrder adds an assertion that the fields are written in ascending tag order, which is
+// the canonical order produced by most Protobuf encoders.  Repeated occurrences of the same tag are
+// allowed.
+func (v *WireValidator) TagsInAscendingOrder() *WireValidator {
+	v.checks = append(v.checks, func(fields []wireField) string {
+		for i := 1; i < len(fields); i++ {
+			if fields[i].tag < fields[i-1].tag {
+				return fmt.Sprintf("tag %d at byte %d follows tag %d, expected tags in ascending order", fields[i].tag, fields[i].offset, fields[i-1].tag)
+			}
+		}
+		return ""
+	})
+	return v
+}
+
+// NoUnknownWireTypes adds an assertion that every field has one of the wire types defined by the
+// Protobuf spec.
+func (v *WireValidator) NoUnknownWireTypes() *WireValidator {
+	v.checks = append(v.checks, func(fields []wireField) string {
+		for _, f := range fields {
+			if !isKnownWireType(f.wt) {
+				return fmt.Sprintf("unknown wire type %d for tag %d at byte %d", int(f.wt), f.tag, f.offset)
+			}
+		}
+		return ""
+	})
+	return v
+}
+
+// Validate parses data and reports a test failure via t for each registered assertion that does not
+// hold.  A failure is also reported if data is not well-formed Protobuf data.  The return value
+// indicates whether or not all of the assertions passed.
+func (v *WireValidator) Validate(t testing.TB, data []byte) bool {
+	t.Helper()
+	fields, err := scanWireFields(data)
+	ok := true
+	if err != nil {
+		t.Errorf("invalid Protobuf data: %v\n%s", err, HexDump(data))
+		ok = false
+	}
+	for _, check := range v.checks {
+		if msg := check(fields); msg != "" {
+			t.Errorf("%s\n%s", msg, HexDump(data))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// scanWireFields returns the tag and wire type of each top-level field in data.
+//
+// Scanning stops without an error at the first field with an unknown wire type, which is included in
+// the result, since the size of the value cannot be determined.
+func scanWireFields(data []byte) ([]wireField, error) {
+	var fields []wireField
+	dec := csproto.NewDecoder(data)
+	for dec.More() {
+		offset := dec.Offset()
+		tag, wt, err := dec.DecodeTag()
+		if err != nil {
+			return fields, fmt.Errorf("unable to read tag at byte %d: %w", offset, err)
+		}
+		fields = append(fields, wireField{offset: offset, tag: tag, wt: wt})
+		if !isKnownWireType(wt) {
+			return fields, nil
+		}
+		if _, err := dec.Skip(tag, wt); err != nil {
+			return fields, fmt.Errorf("unable to read value for tag %d at byte %d: %w", tag, offset, err)
+		}
+	}
+	return fields, nil
+}
+
+// isKnownWireType returns true if wt is one of the wire types defined by the Protobuf spec.
+func isKnownWireType(wt csproto.WireType) bool {
+	switch wt {
+	case csproto.WireTypeVarint, csproto.WireTypeFixed64, csproto.WireTypeLengthDelimited,
+		csproto.WireTypeStartGroup, csproto.WireTypeEndGroup, csproto.WireTypeFixed32:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,131 @@
+package prototest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/prototest"
+)
+
+// recordingTB is a testing.TB that records reported errors rather than failing the test
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestWireValidator(t *testing.T) {
+	t.Parallel()
+	var sampleMessage = []byte{
+		// field 1: varint 150
+		(1 << 3), 0x96, 0x01,
+		// field 2: string "testing"
+		(2 << 3) | 2, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		// field 3: fixed32 1138, repeated
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+		(3 << 3) | 5, 0x72, 0x04, 0x00, 0x00,
+	}
+	cases := []struct {
+		name       string
+		data       []byte
+		setup      func(*prototest.WireValidator)
+		shouldFail bool
+	}{
+		{
+			name:  "no assertions",
+			data:  sampleMessage,
+			setup: func(*prototest.WireValidator) {},
+		},
+		{
+			name: "all assertions pass",
+			data: sampleMessage,
+			setup: func(v *prototest.WireValidator) {
+				v.HasTag(1, csproto.WireTypeVarint).
+					HasTag(2, csproto.WireTypeLengthDelimited).
+					HasTagCount(3, 2).
+					HasTagCount(4, 0).
+					TagsInAscendingOrder().
+					NoUnknownWireTypes()
+			},
+		},
+		{
+			name: "missing tag",
+			data: sampleMessage,
+			setup: func(v *prototest.WireValidator) {
+				v.HasTag(4, csproto.WireTypeVarint)
+			},
+			shouldFail: true,
+		},
+		{
+			name: "wrong wire type",
+			data: sampleMessage,
+			setup: func(v *prototest.WireValidator) {
+				v.HasTag(1, csproto.WireTypeFixed64)
+			},
+			shouldFail: true,
+		},
+		{
+			name: "wrong tag count",
+			data: sampleMessage,
+			setup: func(v *prototest.WireValidator) {
+				v.HasTagCount(3, 1)
+			},
+			shouldFail: true,
+		},
+		{
+			name: "tags out of order",
+			data: []byte{(2 << 3), 0x01, (1 << 3), 0x01},
+			setup: func(v *prototest.WireValidator) {
+				v.TagsInAscendingOrder()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "unknown wire type",
+			data: []byte{(1 << 3), 0x01, (2 << 3) | 6, 0x01},
+			setup: func(v *prototest.WireValidator) {
+				v.NoUnknownWireTypes()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "group wire types are known",
+			data: []byte{(1 << 3) | 3, (2 << 3), 0x01, (1 << 3) | 4},
+			setup: func(v *prototest.WireValidator) {
+				v.HasTag(1, csproto.WireTypeStartGroup).NoUnknownWireTypes()
+			},
+		},
+		{
+			name:       "truncated data",
+			data:       sampleMessage[:6],
+			setup:      func(*prototest.WireValidator) {},
+			shouldFail: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var v prototest.WireValidator
+			tc.setup(&v)
+			rtb := &recordingTB{TB: t}
+
+			ok := v.Validate(rtb, tc.data)
+			if tc.shouldFail {
+				assert.False(t, ok)
+				assert.NotEmpty(t, rtb.errors)
+			} else {
+				assert.True(t, ok)
+				assert.Empty(t, rtb.errors)
+			}
+		})
+	}
+}
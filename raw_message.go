@@ -0,0 +1,124 @@
+package csproto
+
+import (
+	"fmt"
+)
+
+// RawField is a single Protobuf field whose value is held as uninterpreted, pre-encoded bytes.
+//
+// For WireTypeLengthDelimited fields, Data is the content of the field without the varint-encoded
+// length prefix.  For all other wire types, Data is the complete encoded value, e.g. the varint bytes
+// or the 4/8 little-endian bytes of a fixed-width value.
+type RawField struct {
+	Tag      int
+	WireType WireType
+	Data     []byte
+}
+
+// RawMessage is a Protobuf message held as an ordered list of uninterpreted fields.  This is useful
+// for passing data through without decoding it, for example when routing messages whose types are
+// not known at compile time.
+type RawMessage []RawField
+
+// Marshal allocates a buffer large enough to hold the binary Protobuf encoding of m, writes the fields
+// into it in order, then returns the buffer.
+func (m RawMessage) Marshal() ([]byte, error) {
+	return m.Append(nil)
+}
+
+// Append writes the binary Protobuf encoding of m to the end of b, growing it if needed, and returns
+// the updated slice.
+//
+// An error is returned if any field has an invalid tag or wire type or if the length of the data for
+// a fixed-width field does not match its wire type.
+func (m RawMessage) Append(b []byte) ([]byte, error) {
+	sz := 0
+	for i := range m {
+		fsz, err := m[i].size()
+		if err != nil {
+			return nil, err
+		}
+		sz += fsz
+	}
+	l := len(b)
+	if cap(b)-l < sz {
+		nb := make([]byte, l, l+sz)
+		copy(nb, b)
+		b = nb
+	}
+	b = b[:l+sz]
+	enc := NewEncoder(b[l:])
+	for _, f := range m {
+		enc.EncodeRawField(f.Tag, f.WireType, f.Data)
+	}
+	return b, nil
+}
+
+// size validates f and returns the number of bytes required to hold its binary Protobuf encoding.
+func (f *RawField) size() (int, error) {
+	if f.Tag < 1 || f.Tag > MaxTagValue {
+		return 0, fmt.Errorf("invalid raw field tag %d: %w", f.Tag, ErrInvalidFieldTag)
+	}
+	sz := SizeOfTagKey(f.Tag) + len(f.Data)
+	switch f.WireType {
+	case WireTypeVarint:
+		if len(f.Data) == 0 || len(f.Data) > 10 {
+			return 0, fmt.Errorf("invalid data length %d for varint raw field %d: %w", len(f.Data), f.Tag, ErrInvalidVarintData)
+		}
+	case WireTypeFixed64:
+		if len(f.Data) != 8 {
+			return 0, fmt.Errorf("invalid data length %d for fixed64 raw field %d: %w", len(f.Data), f.Tag, ErrInvalidFixed64Data)
+		}
+	case WireTypeFixed32:
+		if len(f.Data) != 4 {
+			return 0, fmt.Errorf("invalid data length %d for fixed32 raw field %d: %w", len(f.Data), f.Tag, ErrInvalidFixed32Data)
+		}
+	case WireTypeLengthDelimited:
+		if len(f.Data) > maxFieldLen {
+			return 0, fmt.Errorf("invalid data length %d for length-delimited raw field %d: %w", len(f.Data), f.Tag, ErrLenOverflow)
+		}
+		sz += SizeOfVarint(uint64(len(f.Data)))
+	default:
+		return 0, fmt.Errorf("unsupported wire type %d for raw field %d", int(f.WireType), f.Tag)
+	}
+	return sz, nil
+}
+
+// DecodeRawMessage reads all of the fields in data as RawField values without interpreting them.
+//
+// The Data of each returned field refers to the underlying array of data rather than a copy, so the
+// caller must not modify data while the result is in use.
+func DecodeRawMessage(data []byte) (RawMessage, error) {
+	var (
+		msg RawMessage
+		dec = NewDecoder(data)
+	)
+	for dec.More() {
+		tag, wt, err := dec.DecodeTag()
+		if err != nil {
+			return nil, err
+		}
+		start := dec.Offset()
+		switch wt {
+		case WireTypeVarint:
+			_, err = dec.DecodeUInt64()
+		case WireTypeFixed64:
+			_, err = dec.DecodeFixed64()
+		case WireTypeFixed32:
+			_, err = dec.DecodeFixed32()
+		case WireTypeLengthDelimited:
+			var b []byte
+			if b, err = dec.DecodeBytes(); err == nil {
+				msg = append(msg, RawField{Tag: tag, WireType: wt, Data: b})
+				continue
+			}
+		default:
+			err = fmt.Errorf("unsupported wire type %d for tag %d at byte %d", int(wt), tag, start)
+		}
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, RawField{Tag: tag, WireType: wt, Data: data[start:dec.Offset()]})
+	}
+	return msg, nil
+}
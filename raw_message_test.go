@@ -0,0 +1,125 @@
+package csproto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+func TestRawMessageMarshal(t *testing.T) {
+	t.Parallel()
+	msg := csproto.RawMessage{
+		{Tag: 1, WireType: csproto.WireTypeVarint, Data: []byte{0x96, 0x01}},
+		{Tag: 2, WireType: csproto.WireTypeFixed64, Data: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}},
+		{Tag: 3, WireType: csproto.WireTypeLengthDelimited, Data: []byte("this is a test")},
+		{Tag: 4, WireType: csproto.WireTypeFixed32, Data: []byte{0x04, 0x03, 0x02, 0x01}},
+		{Tag: 5, WireType: csproto.WireTypeLengthDelimited, Data: []byte{}},
+	}
+	expected := make([]byte, 3+9+16+5+2)
+	enc := csproto.NewEncoder(expected)
+	enc.EncodeUInt64(1, 150)
+	enc.EncodeFixed64(2, 0x0102030405060708)
+	enc.EncodeString(3, "this is a test")
+	enc.EncodeFixed32(4, 0x01020304)
+	enc.EncodeBytes(5, []byte{})
+
+	t.Run("marshal", func(t *testing.T) {
+		t.Parallel()
+		got, err := msg.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+	t.Run("append", func(t *testing.T) {
+		t.Parallel()
+		prefix := []byte{0x01, 0x02, 0x03}
+		got, err := msg.Append(prefix)
+		assert.NoError(t, err)
+		assert.Equal(t, append([]byte{0x01, 0x02, 0x03}, expected...), got)
+	})
+	t.Run("append with spare capacity", func(t *testing.T) {
+		t.Parallel()
+		buf := make([]byte, 0, len(expected))
+		got, err := msg.Append(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+		assert.Same(t, &buf[:1][0], &got[0], "Append() should reuse the existing buffer")
+	})
+	t.Run("empty message", func(t *testing.T) {
+		t.Parallel()
+		got, err := csproto.RawMessage{}.Marshal()
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestRawMessageMarshalInvalidFields(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		field csproto.RawField
+	}{
+		{name: "zero tag", field: csproto.RawField{Tag: 0, WireType: csproto.WireTypeVarint, Data: []byte{0x01}}},
+		{name: "tag too large", field: csproto.RawField{Tag: csproto.MaxTagValue + 1, WireType: csproto.WireTypeVarint, Data: []byte{0x01}}},
+		{name: "empty varint", field: csproto.RawField{Tag: 1, WireType: csproto.WireTypeVarint}},
+		{name: "short fixed64", field: csproto.RawField{Tag: 1, WireType: csproto.WireTypeFixed64, Data: []byte{0x01, 0x02}}},
+		{name: "long fixed32", field: csproto.RawField{Tag: 1, WireType: csproto.WireTypeFixed32, Data: []byte{0x01, 0x02, 0x03, 0x04, 0x05}}},
+		{name: "unsupported wire type", field: csproto.RawField{Tag: 1, WireType: csproto.WireType(3)}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := csproto.RawMessage{tc.field}.Marshal()
+			assert.Error(t, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestDecodeRawMessage(t *testing.T) {
+	t.Parallel()
+	t.Run("all wire types", func(t *testing.T) {
+		t.Parallel()
+		data := make([]byte, 3+9+5+5+11)
+		enc := csproto.NewEncoder(data)
+		enc.EncodeUInt64(1, 150)
+		enc.EncodeFixed64(2, 0x0102030405060708)
+		enc.EncodeString(3, "foo")
+		enc.EncodeFixed32(4, 0x01020304)
+		enc.EncodeInt64(1, -1)
+
+		msg, err := csproto.DecodeRawMessage(data)
+		assert.NoError(t, err)
+		expected := csproto.RawMessage{
+			{Tag: 1, WireType: csproto.WireTypeVarint, Data: []byte{0x96, 0x01}},
+			{Tag: 2, WireType: csproto.WireTypeFixed64, Data: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}},
+			{Tag: 3, WireType: csproto.WireTypeLengthDelimited, Data: []byte("foo")},
+			{Tag: 4, WireType: csproto.WireTypeFixed32, Data: []byte{0x04, 0x03, 0x02, 0x01}},
+			{Tag: 1, WireType: csproto.WireTypeVarint, Data: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}},
+		}
+		assert.Equal(t, expected, msg)
+
+		// round trip
+		got, err := msg.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+	t.Run("empty data", func(t *testing.T) {
+		t.Parallel()
+		msg, err := csproto.DecodeRawMessage(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, msg)
+	})
+	t.Run("truncated data", func(t *testing.T) {
+		t.Parallel()
+		_, err := csproto.DecodeRawMessage([]byte{0x1A, 0x03, 'f', 'o'})
+		assert.Error(t, err)
+	})
+	t.Run("unsupported wire type", func(t *testing.T) {
+		t.Parallel()
+		_, err := csproto.DecodeRawMessage([]byte{0x0B, 0x0C})
+		assert.Error(t, err)
+	})
+}
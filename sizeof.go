@@ -21,6 +21,24 @@ func SizeOfZigZag(v uint64) int {
 	return SizeOfVarint((v << 1) ^ uint64((int64(v) >> 63)))
 }
 
+// SizeOfSInt32 returns the number of bytes required to hold a sint32 field with the specified tag
+// and value, including the tag key.
+func SizeOfSInt32(tag int, v int32) int {
+	return SizeOfTagKey(tag) + SizeOfZigZag(uint64(int64(v)))
+}
+
+// SizeOfSInt64 returns the number of bytes required to hold a sint64 field with the specified tag
+// and value, including the tag key.
+func SizeOfSInt64(tag int, v int64) int {
+	return SizeOfTagKey(tag) + SizeOfZigZag(uint64(v))
+}
+
+// SizeOfGroup returns the number of bytes required to hold a proto2 group field with the specified
+// tag and dataLen bytes of encoded contents, including the start-group and end-group tag keys.
+func SizeOfGroup(tag int, dataLen int) int {
+	return 2*SizeOfTagKey(tag) + dataLen
+}
+
 // Size returns the encoded size of msg.
 func Size(msg interface{}) int {
 	if pm, ok := msg.(Sizer); ok {
@@ -123,3 +123,80 @@ func TestSizeOfVarint(t *testing.T) {
 		})
 	}
 }
+
+func TestSizeOfSInt32(t *testing.T) {
+	cases := []struct {
+		name     string
+		tag      int
+		v        int32
+		expected int
+	}{
+		{name: "min value", tag: 1, v: math.MinInt32, expected: 1 + 5},
+		{name: "-1", tag: 1, v: -1, expected: 1 + 1},
+		{name: "zero", tag: 1, v: 0, expected: 1 + 1},
+		{name: "max value", tag: 1, v: math.MaxInt32, expected: 1 + 5},
+		{name: "multi-byte tag", tag: 16, v: -64, expected: 2 + 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := csproto.SizeOfSInt32(tc.tag, tc.v)
+			assert.Equal(t, tc.expected, got)
+
+			// the encoder should exactly fill a buffer of the calculated size
+			buf := make([]byte, got)
+			enc := csproto.NewEncoder(buf)
+			assert.NotPanics(t, func() { enc.EncodeSInt32(tc.tag, tc.v) })
+			v, err := csproto.NewDecoder(buf[csproto.SizeOfTagKey(tc.tag):]).DecodeSInt32()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.v, v)
+		})
+	}
+}
+
+func TestSizeOfSInt64(t *testing.T) {
+	cases := []struct {
+		name     string
+		tag      int
+		v        int64
+		expected int
+	}{
+		{name: "min value", tag: 1, v: math.MinInt64, expected: 1 + 10},
+		{name: "-1", tag: 1, v: -1, expected: 1 + 1},
+		{name: "zero", tag: 1, v: 0, expected: 1 + 1},
+		{name: "max value", tag: 1, v: math.MaxInt64, expected: 1 + 10},
+		{name: "multi-byte tag", tag: 16, v: 64, expected: 2 + 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := csproto.SizeOfSInt64(tc.tag, tc.v)
+			assert.Equal(t, tc.expected, got)
+
+			// the encoder should exactly fill a buffer of the calculated size
+			buf := make([]byte, got)
+			enc := csproto.NewEncoder(buf)
+			assert.NotPanics(t, func() { enc.EncodeSInt64(tc.tag, tc.v) })
+			v, err := csproto.NewDecoder(buf[csproto.SizeOfTagKey(tc.tag):]).DecodeSInt64()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.v, v)
+		})
+	}
+}
+
+func TestSizeOfGroup(t *testing.T) {
+	cases := []struct {
+		name     string
+		tag      int
+		dataLen  int
+		expected int
+	}{
+		{name: "empty group", tag: 1, dataLen: 0, expected: 2},
+		{name: "single-byte tag", tag: 15, dataLen: 10, expected: 2 + 10},
+		{name: "multi-byte tag", tag: 16, dataLen: 10, expected: 4 + 10},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := csproto.SizeOfGroup(tc.tag, tc.dataLen)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
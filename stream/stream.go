@@ -0,0 +1,151 @@
+// Package stream provides an Encoder and Decoder for reading and writing streams of Protobuf messages
+// where each message is preceded by its encoded length.
+//
+// Each message in the stream is written as a 4-byte, big-endian, unsigned length followed by the
+// binary Protobuf encoding of the message.
+package stream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/lazyproto"
+)
+
+// lengthPrefixSize is the number of bytes used to encode the length of each message in the stream
+const lengthPrefixSize = 4
+
+// DefaultMaxMessageSize is the maximum size of a single message accepted by a [Decoder] unless a
+// different limit is configured using [WithMaxMessageSize].
+const DefaultMaxMessageSize = 4 << 20
+
+// Encoder writes length-prefixed Protobuf messages to an [io.Writer].
+type Encoder struct {
+	w      io.Writer
+	header [lengthPrefixSize]byte
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the length-prefixed binary Protobuf encoding of msg to the underlying writer.
+//
+// Like [csproto.Marshal], msg can be a message generated by any of the supported Protobuf runtimes.  If
+// the encoded message is too large for the 4-byte length prefix, the returned error wraps
+// [csproto.ErrMessageTooLarge].
+func (e *Encoder) Encode(msg interface{}) error {
+	data, err := csproto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal message: %w", err)
+	}
+	if uint64(len(data)) > math.MaxUint32 {
+		return fmt.Errorf("%w: %d bytes cannot be represented by the length prefix", csproto.ErrMessageTooLarge, len(data))
+	}
+	binary.BigEndian.PutUint32(e.header[:], uint32(len(data)))
+	if _, err = e.w.Write(e.header[:]); err != nil {
+		return fmt.Errorf("unable to write message length: %w", err)
+	}
+	if _, err = e.w.Write(data); err != nil {
+		return fmt.Errorf("unable to write message data: %w", err)
+	}
+	return nil
+}
+
+// Decoder reads length-prefixed Protobuf messages from an [io.Reader].
+//
+// Reads from the underlying reader are done using [io.ReadFull] so fragmented data, such as data read
+// from a network connection, is handled correctly.
+type Decoder struct {
+	r       io.Reader
+	header  [lengthPrefixSize]byte
+	maxSize int
+}
+
+// DecoderOption defines a function that sets a specific decoder option
+type DecoderOption func(*Decoder)
+
+// WithMaxMessageSize returns a DecoderOption that limits the size of the messages that can be read from
+// the stream.  If the length prefix of the next message is larger than n bytes, the decoder returns an
+// error that wraps [csproto.ErrMessageTooLarge] without reading or allocating space for the message
+// data.  A value of zero or less disables the limit.
+//
+// The default limit is [DefaultMaxMessageSize].
+func WithMaxMessageSize(n int) DecoderOption {
+	return func(d *Decoder) {
+		d.maxSize = n
+	}
+}
+
+// NewDecoder returns a new Decoder that reads from r.  The behavior of the decoder can be customized by
+// passing one or more DecoderOption values.
+func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
+	d := &Decoder{
+		r:       r,
+		maxSize: DefaultMaxMessageSize,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	return d
+}
+
+// Decode reads the next message from the stream and unmarshals it into msg.
+//
+// Like [csproto.Unmarshal], msg can be a message generated by any of the supported Protobuf runtimes.
+// If there are no more messages in the stream, Decode returns [io.EOF].  If the stream ends in the
+// middle of a message, the returned error wraps [io.ErrUnexpectedEOF].  If the message is larger than
+// the limit set by [WithMaxMessageSize], the returned error wraps [csproto.ErrMessageTooLarge] and the
+// message data is left unread, so the stream cannot be read any further.
+func (d *Decoder) Decode(msg interface{}) error {
+	data, err := d.next()
+	if err != nil {
+		return err
+	}
+	if err = csproto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("unable to unmarshal message: %w", err)
+	}
+	return nil
+}
+
+// DecodePartial reads the next message from the stream and extracts the fields in def using
+// [lazyproto.Decode].
+//
+// As with [lazyproto.Decode], callers should call Close() on the returned result when they are done
+// with it.  See [Decoder.Decode] for details on the errors returned at the end of the stream.
+func (d *Decoder) DecodePartial(def lazyproto.Def) (lazyproto.DecodeResult, error) {
+	data, err := d.next()
+	if err != nil {
+		return lazyproto.DecodeResult{}, err
+	}
+	return lazyproto.Decode(data, def)
+}
+
+// next reads the next length-prefixed message from the stream and returns the message data.
+func (d *Decoder) next() ([]byte, error) {
+	if _, err := io.ReadFull(d.r, d.header[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("unable to read message length: %w", err)
+	}
+	l := binary.BigEndian.Uint32(d.header[:])
+	if d.maxSize > 0 && uint64(l) > uint64(d.maxSize) {
+		return nil, fmt.Errorf("%w: %d bytes is larger than the limit of %d bytes", csproto.ErrMessageTooLarge, l, d.maxSize)
+	}
+	// always allocate a new buffer since the decoded message, or the partial decode result, may
+	// reference the data
+	data := make([]byte, l)
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("unable to read message data: %w", err)
+	}
+	return data, nil
+}
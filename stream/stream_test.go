@@ -0,0 +1,160 @@
+package stream_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/CrowdStrike/csproto"
+	"github.com/CrowdStrike/csproto/lazyproto"
+	"github.com/CrowdStrike/csproto/stream"
+)
+
+func TestEncodeDecodeWithPipe(t *testing.T) {
+	t.Parallel()
+	msgs := []*wrapperspb.StringValue{
+		wrapperspb.String("one"),
+		wrapperspb.String(""),
+		wrapperspb.String("three"),
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		enc := stream.NewEncoder(pw)
+		for _, m := range msgs {
+			if err := enc.Encode(m); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		_ = pw.Close()
+	}()
+
+	// read one byte at a time to simulate fragmented reads from a network connection
+	dec := stream.NewDecoder(iotest.OneByteReader(pr))
+	for i, expected := range msgs {
+		var got wrapperspb.StringValue
+		require.NoError(t, dec.Decode(&got), "error decoding message %d", i)
+		assert.True(t, proto.Equal(expected, &got), "mismatched message %d: expected %v, got %v", i, expected, &got)
+	}
+	var extra wrapperspb.StringValue
+	assert.ErrorIs(t, dec.Decode(&extra), io.EOF)
+}
+
+func TestEncodeWireFormat(t *testing.T) {
+	t.Parallel()
+	msg := wrapperspb.String("testing")
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, stream.NewEncoder(&buf).Encode(msg))
+
+	// each message is a 4-byte, big-endian length followed by the message data
+	expected := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	expected = append(expected, data...)
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+func TestDecodePartial(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	enc := stream.NewEncoder(&buf)
+	require.NoError(t, enc.Encode(wrapperspb.String("one")))
+	require.NoError(t, enc.Encode(wrapperspb.String("two")))
+
+	dec := stream.NewDecoder(&buf)
+	for _, expected := range []string{"one", "two"} {
+		res, err := dec.DecodePartial(lazyproto.NewDef(1))
+		require.NoError(t, err)
+		fd, err := res.FieldData(1)
+		require.NoError(t, err)
+		got, err := fd.StringValue()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+		_ = res.Close()
+	}
+	_, err := dec.DecodePartial(lazyproto.NewDef(1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestDecodeTruncatedStream(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	require.NoError(t, stream.NewEncoder(&buf).Encode(wrapperspb.String("testing")))
+	data := buf.Bytes()
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated length", data: data[:2]},
+		{name: "truncated message", data: data[:len(data)-1]},
+		{name: "missing message", data: data[:4]},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var msg wrapperspb.StringValue
+			err := stream.NewDecoder(bytes.NewReader(tc.data)).Decode(&msg)
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+}
+
+func TestDecodeMessageTooLarge(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	require.NoError(t, stream.NewEncoder(&buf).Encode(wrapperspb.String("testing")))
+	data := buf.Bytes()
+
+	t.Run("default limit", func(t *testing.T) {
+		t.Parallel()
+		// a length prefix of 0xFFFFFFFF with no message data
+		dec := stream.NewDecoder(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF}))
+		var msg wrapperspb.StringValue
+		err := dec.Decode(&msg)
+		assert.ErrorIs(t, err, csproto.ErrMessageTooLarge)
+		assert.NotErrorIs(t, err, io.ErrUnexpectedEOF, "should fail before reading the message data")
+	})
+	t.Run("configured limit", func(t *testing.T) {
+		t.Parallel()
+		dec := stream.NewDecoder(bytes.NewReader(data), stream.WithMaxMessageSize(len(data)-5))
+		var msg wrapperspb.StringValue
+		assert.ErrorIs(t, dec.Decode(&msg), csproto.ErrMessageTooLarge)
+	})
+	t.Run("message at limit", func(t *testing.T) {
+		t.Parallel()
+		dec := stream.NewDecoder(bytes.NewReader(data), stream.WithMaxMessageSize(len(data)-4))
+		var msg wrapperspb.StringValue
+		assert.NoError(t, dec.Decode(&msg))
+		assert.Equal(t, "testing", msg.GetValue())
+	})
+	t.Run("limit disabled", func(t *testing.T) {
+		t.Parallel()
+		// a length prefix of 8 MiB, which is larger than the default limit, with no message data
+		dec := stream.NewDecoder(bytes.NewReader([]byte{0x00, 0x80, 0x00, 0x00}), stream.WithMaxMessageSize(0))
+		var msg wrapperspb.StringValue
+		assert.ErrorIs(t, dec.Decode(&msg), io.ErrUnexpectedEOF)
+	})
+}
+
+func TestEncodeWriteError(t *testing.T) {
+	t.Parallel()
+	err := stream.NewEncoder(errWriter{}).Encode(wrapperspb.String("testing"))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
@@ -0,0 +1,44 @@
+package csproto
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Transform returns a copy of msg where the value of each populated field has been replaced by the
+// result of calling fn with that field's descriptor and current value.  The original message is not
+// modified.
+//
+// Only fields that are set on the message are passed to fn, and fn is called once per field rather than
+// once per element for repeated and map fields.  If fn returns an invalid [protoreflect.Value], which
+// is the zero value, the field is cleared.  Nested messages are not traversed, so fn should recurse
+// into message values itself if needed.
+//
+// If msg is nil, this function returns nil.
+func Transform(msg proto.Message, fn func(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value) proto.Message {
+	if msg == nil {
+		return nil
+	}
+	res := proto.Clone(msg)
+	m := res.ProtoReflect()
+	// collect the field values before making any changes since modifying a message while iterating
+	// over it with Range() is undefined behavior
+	type fieldValue struct {
+		fd protoreflect.FieldDescriptor
+		v  protoreflect.Value
+	}
+	var fields []fieldValue
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fields = append(fields, fieldValue{fd: fd, v: v})
+		return true
+	})
+	for _, f := range fields {
+		nv := fn(f.fd, f.v)
+		if !nv.IsValid() {
+			m.Clear(f.fd)
+			continue
+		}
+		m.Set(f.fd, nv)
+	}
+	return res
+}
@@ -11,11 +11,13 @@ const (
 	// WireTypeLengthDelimited denotes a value that is encoded as a sequence of bytes preceded by a
 	// varint-encoded length.
 	WireTypeLengthDelimited WireType = 2
+	// WireTypeStartGroup denotes the start of a deprecated proto2 group.  The group contents are the
+	// encoded fields that follow, up to the matching WireTypeEndGroup tag.
+	WireTypeStartGroup WireType = 3
+	// WireTypeEndGroup denotes the end of a deprecated proto2 group.
+	WireTypeEndGroup WireType = 4
 	// WireTypeFixed32 denotes a value that is encoded using 4 bytes.
 	WireTypeFixed32 WireType = 5
-
-	// WireTypeStartGroup      WireType = 3
-	// WireTypeEndGroup        WireType = 4
 )
 
 var (
@@ -23,6 +25,8 @@ var (
 		WireTypeVarint:          "varint",
 		WireTypeFixed64:         "fixed64",
 		WireTypeLengthDelimited: "length-delimited",
+		WireTypeStartGroup:      "start_group",
+		WireTypeEndGroup:        "end_group",
 		WireTypeFixed32:         "fixed32",
 	}
 )
@@ -0,0 +1,42 @@
+package csproto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/CrowdStrike/csproto"
+)
+
+func TestWireTypeValues(t *testing.T) {
+	// the constants must match the values defined by the Protobuf spec
+	assert.Equal(t, csproto.WireType(protowire.VarintType), csproto.WireTypeVarint)
+	assert.Equal(t, csproto.WireType(protowire.Fixed64Type), csproto.WireTypeFixed64)
+	assert.Equal(t, csproto.WireType(protowire.BytesType), csproto.WireTypeLengthDelimited)
+	assert.Equal(t, csproto.WireType(protowire.StartGroupType), csproto.WireTypeStartGroup)
+	assert.Equal(t, csproto.WireType(protowire.EndGroupType), csproto.WireTypeEndGroup)
+	assert.Equal(t, csproto.WireType(protowire.Fixed32Type), csproto.WireTypeFixed32)
+}
+
+func TestWireTypeString(t *testing.T) {
+	cases := []struct {
+		wt       csproto.WireType
+		expected string
+	}{
+		{wt: csproto.WireTypeVarint, expected: "varint"},
+		{wt: csproto.WireTypeFixed64, expected: "fixed64"},
+		{wt: csproto.WireTypeLengthDelimited, expected: "length-delimited"},
+		{wt: csproto.WireTypeStartGroup, expected: "start_group"},
+		{wt: csproto.WireTypeEndGroup, expected: "end_group"},
+		{wt: csproto.WireTypeFixed32, expected: "fixed32"},
+		{wt: csproto.WireType(6), expected: "unknown"},
+		{wt: csproto.WireType(7), expected: "unknown"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.wt.String())
+		})
+	}
+}